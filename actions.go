@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Actions that can be listed under on_success.actions.
+const (
+	actionCopy      = "copy"
+	actionOpen      = "open"
+	actionPrintJSON = "print-json"
+)
+
+// webhookTimeout bounds the whole webhook call, so an endpoint that is down
+// does not hold up the command.
+const webhookTimeout = 10 * time.Second
+
+type OnSuccessConfig struct {
+	Actions    []string `yaml:"actions"`
+	WebhookURL string   `yaml:"webhook_url"`
+}
+
+func validateActions(actions []string) error {
+	for _, a := range actions {
+		switch a {
+		case actionCopy, actionOpen, actionPrintJSON:
+		default:
+			return fmt.Errorf("unknown on_success action %q, expected %s, %s or %s", a, actionCopy, actionOpen, actionPrintJSON)
+		}
+	}
+	return nil
+}
+
+// runAction performs a single on_success action for a created issue.
+func runAction(action string, c Config, issue *jira.Issue) error {
+	url := browseURL(c, issue.Key)
+	switch action {
+	case actionCopy:
+		return clipboard.WriteAll(url)
+	case actionOpen:
+		return openURL(url)
+	case actionPrintJSON:
+		return writeIssueJSON(os.Stdout, c, issue)
+	}
+	return nil
+}
+
+// postWebhook notifies an incoming webhook (Slack, Teams, ...) about a
+// created issue.
+func postWebhook(webhookURL string, c Config, issue *jira.Issue, summary string) error {
+	body, err := json.Marshal(map[string]string{
+		"key":     issue.Key,
+		"summary": summary,
+		"url":     browseURL(c, issue.Key),
+	})
+	if err != nil {
+		return err
+	}
+
+	client := http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// tuiActions runs the on_success actions that make sense while the TUI is
+// up, one after the other, reporting each on the success screen. Printing is
+// left until the TUI has exited.
+func tuiActions(c Config, issue *jira.Issue) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, action := range c.OnSuccess.Actions {
+		if action == actionPrintJSON {
+			continue
+		}
+		action := action
+		cmds = append(cmds, func() tea.Msg {
+			if err := runAction(action, c, issue); err != nil {
+				return noticeMsg(fmt.Sprintf("%s failed: %v", action, err))
+			}
+			return noticeMsg(fmt.Sprintf("%s done", action))
+		})
+	}
+	return tea.Sequence(cmds...)
+}
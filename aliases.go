@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fieldID resolves a field_aliases name, like story_points, to the field id
+// it stands for. Anything else is returned as is.
+func (c Config) fieldID(name string) string {
+	if id, ok := c.FieldAliases[name]; ok {
+		return id
+	}
+	return name
+}
+
+// validateFieldAliases checks every alias points at a field id.
+func validateFieldAliases(aliases map[string]string) error {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		id := strings.TrimSpace(aliases[name])
+		if id == "" {
+			return fmt.Errorf("field_aliases: %s has no field id", name)
+		}
+		if other, ok := aliases[id]; ok {
+			return fmt.Errorf("field_aliases: %s points at %s, itself an alias for %s", name, id, other)
+		}
+	}
+	return nil
+}
+
+// useFieldAliases replaces aliases with field ids in the -field flags and
+// the create_issue.custom_fields, before anything is looked up by them.
+func (c *Config) useFieldAliases(args []fieldArg) []fieldArg {
+	if len(c.FieldAliases) == 0 {
+		return args
+	}
+	for i := range args {
+		args[i].field = c.fieldID(args[i].field)
+	}
+	aliasKeys(*c, c.CreateIssue.CustomFields)
+	return args
+}
+
+// aliasKeys replaces the aliases among the keys of fields, like those of a
+// batch row, with field ids.
+func aliasKeys(c Config, fields map[string]interface{}) {
+	for name, v := range fields {
+		if id := c.fieldID(name); id != name {
+			delete(fields, name)
+			fields[id] = v
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// approval is a JSM approval step waiting on a request.
+type approval struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	FinalDecision     string `json:"finalDecision"`
+	CanAnswerApproval bool   `json:"canAnswerApproval"`
+}
+
+// approveIssue approves the pending approvals of a service desk request, as
+// create_issue.auto_approve asks for. This only gets anywhere when the user
+// is one of the approvers, approvals the user cannot answer are left alone.
+// It returns the names of the approvals given.
+func approveIssue(client *jira.Client, key string) ([]string, error) {
+	var page struct {
+		Values []approval `json:"values"`
+	}
+	if _, err := doRequest(client, "GET", fmt.Sprintf("rest/servicedeskapi/request/%s/approval", key), nil, &page); err != nil {
+		return nil, err
+	}
+
+	var approved []string
+	for _, a := range page.Values {
+		if a.FinalDecision != "pending" || !a.CanAnswerApproval {
+			continue
+		}
+		endpoint := fmt.Sprintf("rest/servicedeskapi/request/%s/approval/%s", key, a.ID)
+		if _, err := doRequest(client, "POST", endpoint, map[string]string{"decision": "approve"}, nil); err != nil {
+			return approved, fmt.Errorf("%s: %w", a.Name, err)
+		}
+		approved = append(approved, a.Name)
+	}
+	if len(approved) == 0 && len(page.Values) > 0 {
+		return nil, fmt.Errorf("no pending approval of %s can be answered by you", key)
+	}
+	return approved, nil
+}
+
+// serviceDeskProjects tells which projects are service desks, looking each
+// one up once. Only their issues can have approvals to give.
+type serviceDeskProjects struct {
+	client *jira.Client
+	desks  map[string]bool
+}
+
+func newServiceDeskProjects(client *jira.Client) *serviceDeskProjects {
+	return &serviceDeskProjects{client: client, desks: map[string]bool{}}
+}
+
+// has reports whether the project is a service desk. When that cannot be
+// told it is taken to be one, so the approval call reports what went wrong.
+func (s *serviceDeskProjects) has(project string) bool {
+	if desk, ok := s.desks[project]; ok {
+		return desk
+	}
+	desk, err := findServiceDesk(context.Background(), s.client, project)
+	s.desks[project] = err != nil || desk != nil
+	return s.desks[project]
+}
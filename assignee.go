@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// automaticAssignee is the assignee JIRA reads as "assign automatically",
+// following the project's default assignee and component leads.
+const automaticAssignee = "-1"
+
+// autoAssigns reports whether the project has anyone to assign issues to
+// automatically, a project lead as default assignee or a component with a
+// default assignee of its own.
+func autoAssigns(p *jira.Project) bool {
+	if p.AssigneeType == "PROJECT_LEAD" {
+		return true
+	}
+	for _, c := range p.Components {
+		if c.AssigneeType != "" && c.AssigneeType != "PROJECT_DEFAULT" && c.AssigneeType != "UNASSIGNED" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadProjectAssignees returns the assignee options that come from the
+// project rather than from the assignable users: "Automatic", when the
+// project assigns issues automatically, and "Project Lead". users tells
+// whether the instance refers to users by account id, like Cloud, or by name.
+func loadProjectAssignees(ctx context.Context, client *jira.Client, project string, users []allowedValue) []allowedValue {
+	if len(users) == 0 {
+		return nil
+	}
+	p, _, err := client.Project.GetWithContext(ctx, project)
+	if err != nil {
+		return nil
+	}
+	ref := users[0].ref
+	var values []allowedValue
+	if autoAssigns(p) {
+		values = append(values, allowedValue{id: automaticAssignee, label: "Automatic", ref: ref})
+	}
+	lead := p.Lead.AccountID
+	if ref == "name" {
+		lead = p.Lead.Name
+	}
+	if lead != "" {
+		values = append(values, allowedValue{id: lead, label: "Project Lead (" + p.Lead.DisplayName + ")", ref: ref})
+	}
+	return values
+}
+
+// useProjectAssignees offers the project assignee options on the assignee
+// field, ahead of the assignable users.
+func useProjectAssignees(fields []*formField, values []allowedValue) {
+	if len(values) == 0 {
+		return
+	}
+	for _, f := range fields {
+		if f.id == "assignee" && len(f.allowed) > 0 && f.allowed[0] != values[0] {
+			f.allowed = append(append([]allowedValue{}, values...), f.allowed...)
+		}
+	}
+}
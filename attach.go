@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// attachment is a file to upload to the created issue.
+type attachment struct {
+	path string
+	size int64
+}
+
+// statAttachments checks the -attach files exist before anything is created.
+func statAttachments(paths []string) ([]attachment, error) {
+	files := make([]attachment, len(paths))
+	for i, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s is a directory, not a file to attach", path)
+		}
+		files[i] = attachment{path: path, size: info.Size()}
+	}
+	return files, nil
+}
+
+// attachmentLimit is the largest file JIRA accepts, in bytes, or 0 when it
+// does not say.
+func attachmentLimit(client *jira.Client) (int64, error) {
+	var meta struct {
+		Enabled     bool  `json:"enabled"`
+		UploadLimit int64 `json:"uploadLimit"`
+	}
+	if _, err := doRequest(client, "GET", "rest/api/2/attachment/meta", nil, &meta); err != nil {
+		return 0, err
+	}
+	if !meta.Enabled {
+		return 0, fmt.Errorf("attachments are turned off on this JIRA instance")
+	}
+	return meta.UploadLimit, nil
+}
+
+// checkAttachments warns about files larger than limit, which JIRA would
+// reject, and returns the ones left to upload.
+func checkAttachments(w io.Writer, files []attachment, limit int64) []attachment {
+	if limit <= 0 {
+		return files
+	}
+	var total int64
+	var ok []attachment
+	for _, f := range files {
+		if f.size > limit {
+			fmt.Fprintf(w, "Warning: %s is %s, over the %s JIRA accepts, it is not attached\n", f.path, formatSize(f.size), formatSize(limit))
+			continue
+		}
+		total += f.size
+		ok = append(ok, f)
+	}
+	if len(ok) > 1 {
+		fmt.Fprintf(w, "Attaching %d files, %s in total\n", len(ok), formatSize(total))
+	}
+	return ok
+}
+
+// formatSize renders a byte count like 1.5 MB.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// uploadAttachments uploads the files to the issue one after another,
+// showing how far along each one is on w. It keeps going when one of them
+// fails and returns how many did.
+func uploadAttachments(w io.Writer, client *jira.Client, key string, files []attachment) int {
+	failed := 0
+	for _, f := range files {
+		p := &progress{w: w, name: filepath.Base(f.path), total: f.size, live: isTerminal(w)}
+		p.print()
+		if err := uploadAttachment(client, key, f, p); err != nil {
+			p.finish(fmt.Sprintf("failed: %v", err))
+			failed++
+			continue
+		}
+		p.finish("done")
+	}
+	if len(files) > 1 {
+		fmt.Fprintf(w, "Attached %d of %d files to %s\n", len(files)-failed, len(files), key)
+	}
+	return failed
+}
+
+// uploadAttachment streams a single file to JIRA, counting what is sent on p.
+func uploadAttachment(client *jira.Client, key string, f attachment, p *progress) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		part, err := mw.CreateFormFile("file", filepath.Base(f.path))
+		if err == nil {
+			_, err = io.Copy(part, io.TeeReader(file, p))
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	base := client.GetBaseURL()
+	endpoint := strings.TrimSuffix(base.String(), "/") + "/rest/api/2/issue/" + key + "/attachments"
+	req, err := http.NewRequest("POST", endpoint, pr)
+	if err == nil {
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.Header.Set("X-Atlassian-Token", "no-check")
+		var resp *jira.Response
+		if resp, err = client.Do(req, nil); err != nil {
+			err = jira.NewJiraError(resp, err)
+		} else {
+			resp.Body.Close()
+		}
+	}
+	// Unblocks the writer when the request ended early, p is only safe to
+	// use again once it is done.
+	pr.Close()
+	<-done
+	return err
+}
+
+// progress shows how much of a file has been sent. On a terminal the line
+// is redrawn as the upload goes, elsewhere only the outcome is printed.
+type progress struct {
+	w     io.Writer
+	name  string
+	total int64
+	sent  int64
+	// shown is the last percentage drawn.
+	shown int
+	live  bool
+}
+
+func (p *progress) Write(b []byte) (int, error) {
+	p.sent += int64(len(b))
+	if pct := p.percent(); p.live && pct != p.shown {
+		p.shown = pct
+		p.print()
+	}
+	return len(b), nil
+}
+
+func (p *progress) percent() int {
+	if p.total == 0 {
+		return 100
+	}
+	return int(p.sent * 100 / p.total)
+}
+
+func (p *progress) print() {
+	if p.live {
+		fmt.Fprintf(p.w, "\rAttaching %s (%s) %3d%%", p.name, formatSize(p.total), p.percent())
+	}
+}
+
+func (p *progress) finish(outcome string) {
+	if p.live {
+		fmt.Fprint(p.w, "\r\033[K")
+	}
+	fmt.Fprintf(p.w, "Attaching %s (%s) %s\n", p.name, formatSize(p.total), outcome)
+}
+
+// isTerminal reports whether w is a terminal rather than a file or pipe.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
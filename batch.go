@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"gopkg.in/yaml.v3"
+)
+
+// batchRow is one issue of a batch file.
+type batchRow struct {
+	// ID identifies the row across runs. Rows without one are identified by
+	// their contents.
+	ID          string                 `yaml:"id,omitempty"`
+	Summary     string                 `yaml:"summary"`
+	Description string                 `yaml:"description,omitempty"`
+	Fields      map[string]interface{} `yaml:"fields,omitempty"`
+	// Project and Type override the project and issue type of the run.
+	Project string `yaml:"project,omitempty"`
+	Type    string `yaml:"type,omitempty"`
+	// Reporter files the issue on someone else's behalf, given by email,
+	// account id, user name or display name.
+	Reporter string `yaml:"reporter,omitempty"`
+}
+
+// ledgerKey identifies the row in the ledger.
+func (r batchRow) ledgerKey(project, issueType string) (string, error) {
+	id := r.ID
+	if id == "" {
+		b, err := yaml.Marshal(r)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(b)
+		id = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return project + "/" + issueType + "/" + id, nil
+}
+
+type batchResult struct {
+	created []*jira.Issue
+	skipped int
+	failed  int
+}
+
+func readBatch(path string) ([]batchRow, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rows []batchRow
+	if err := yaml.Unmarshal(b, &rows); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rows, nil
+}
+
+// ledger records which batch rows were already created, so that running an
+// interrupted batch again only creates the rest.
+type ledger struct {
+	path    string
+	entries map[string]string
+}
+
+func openLedger() (*ledger, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	l := &ledger{
+		path:    filepath.Join(dir, "lazyjira", "batch-ledger.yaml"),
+		entries: map[string]string{},
+	}
+	b, err := os.ReadFile(l.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return l, nil
+	case err != nil:
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, &l.entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", l.path, err)
+	}
+	if l.entries == nil {
+		l.entries = map[string]string{}
+	}
+	return l, nil
+}
+
+// record stores the issue created for a row. The file is written right
+// away, an interruption loses at most the row in flight.
+func (l *ledger) record(key, issueKey string) error {
+	l.entries[key] = issueKey
+	b, err := yaml.Marshal(l.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, b, 0o600)
+}
+
+// batchMeta hands out the create metadata of the projects a batch creates
+// issues in, fetching it once per project.
+type batchMeta struct {
+	client   *jira.Client
+	c        CreateIssueConfig
+	projects map[string]*jira.MetaProject
+	errs     map[string]error
+}
+
+func newBatchMeta(client *jira.Client, c CreateIssueConfig) *batchMeta {
+	return &batchMeta{client: client, c: c, projects: map[string]*jira.MetaProject{}, errs: map[string]error{}}
+}
+
+func (b *batchMeta) project(key string) (*jira.MetaProject, error) {
+	key = strings.ToUpper(key)
+	if p, ok := b.projects[key]; ok {
+		return p, b.errs[key]
+	}
+	p, degraded, err := loadProjectMeta(context.Background(), b.client, key, b.c)
+	if degraded {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", key, createMetaForbidden)
+	}
+	b.projects[key], b.errs[key] = p, err
+	return p, err
+}
+
+// rowError is a batch row that cannot be created, along with the part of the
+// row at fault.
+type rowError struct {
+	field string
+	err   error
+}
+
+func (e rowError) Error() string {
+	return fmt.Sprintf("%s: %v", e.field, e.err)
+}
+
+// resolve checks the project, type and fields of a row against create
+// metadata, falling back to the project and type of the run.
+func (b *batchMeta) resolve(row batchRow, project, issueType string) (*jira.MetaProject, *jira.MetaIssueType, error) {
+	if row.Project != "" {
+		project = row.Project
+	}
+	if row.Type != "" {
+		issueType = row.Type
+	}
+	if project == "" {
+		return nil, nil, rowError{"project", errors.New("no project given, set it on the row, use -project or set create_issue.project")}
+	}
+	metaProject, err := b.project(project)
+	if err != nil {
+		return nil, nil, rowError{"project", err}
+	}
+	metaType, err := pickIssueType(metaProject, issueType, b.c)
+	if err != nil {
+		return nil, nil, rowError{"type", err}
+	}
+	if metaType.Fields != nil {
+		for id := range row.Fields {
+			if _, ok := metaType.Fields[id]; !ok {
+				return nil, nil, rowError{"fields." + id, fmt.Errorf("not on the create screen of %s issues in %s", metaType.Name, metaProject.Key)}
+			}
+		}
+	}
+	return metaProject, metaType, nil
+}
+
+// pendingRows counts the rows a batch run will create issues for, leaving
+// out those already created and those that fail to resolve.
+func pendingRows(rows []batchRow, meta *batchMeta, l *ledger, project, issueType string) int {
+	pending := 0
+	for _, row := range rows {
+		metaProject, metaType, err := meta.resolve(row, project, issueType)
+		if err != nil {
+			continue
+		}
+		key, err := row.ledgerKey(metaProject.Key, metaType.Name)
+		if err != nil {
+			continue
+		}
+		if _, ok := l.entries[key]; !ok {
+			pending++
+		}
+	}
+	return pending
+}
+
+// runBatch creates an issue for every row of a batch file. Rows created by an
+// earlier run are skipped and failing rows are reported and left out.
+func runBatch(c Config, client *jira.Client, creator IssueCreator, path, issueType string, fieldArgs []fieldArg) (batchResult, error) {
+	var res batchResult
+
+	rows, err := readBatch(path)
+	if err != nil {
+		return res, err
+	}
+	for _, row := range rows {
+		aliasKeys(c, row.Fields)
+	}
+	l, err := openLedger()
+	if err != nil {
+		return res, err
+	}
+	meta := newBatchMeta(client, c.CreateIssue)
+	reporters := newBatchReporters(client)
+
+	if pending := pendingRows(rows, meta, l, c.CreateIssue.Project, issueType); pending > 0 {
+		proceed, err := confirmBulk(c, fmt.Sprintf("Create %d issues from %s", pending, path), pending, false)
+		if err != nil {
+			return res, err
+		}
+		if !proceed {
+			return res, errors.New("batch not confirmed, nothing was created")
+		}
+	}
+
+	for n, row := range rows {
+		n++
+		metaProject, metaType, err := meta.resolve(row, c.CreateIssue.Project, issueType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Row %d: %v\n", n, err)
+			res.failed++
+			continue
+		}
+		key, err := row.ledgerKey(metaProject.Key, metaType.Name)
+		if err != nil {
+			return res, err
+		}
+		if issueKey, ok := l.entries[key]; ok {
+			fmt.Fprintf(os.Stderr, "Row %d: already created as %s, skipped\n", n, issueKey)
+			res.skipped++
+			continue
+		}
+
+		fields := &jira.IssueFields{
+			Type:        jira.IssueType{Name: metaType.Name},
+			Project:     jira.Project{Key: metaProject.Key},
+			Summary:     sanitizeSummary(row.Summary),
+			Description: normalizeNewlines(row.Description),
+			Unknowns:    c.CreateIssue.CustomFields.Clone(),
+		}
+		extra, err := resolveFieldArgs(fieldArgs, metaType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Row %d: %v\n", n, err)
+			res.failed++
+			continue
+		}
+		for k, v := range extra {
+			fields.Unknowns[k] = v
+		}
+		for k, v := range row.Fields {
+			fields.Unknowns[k] = v
+		}
+		if row.Reporter != "" {
+			reporter, err := reporters.find(row.Reporter)
+			if errors.Is(err, errUnknownReporter) {
+				fmt.Fprintf(os.Stderr, "Row %d: reporter: %v, skipped\n", n, err)
+				res.skipped++
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Row %d: reporter: %v\n", n, err)
+				res.failed++
+				continue
+			}
+			fields.Reporter = reporter
+		}
+		c.CreateIssue.transformLabels(fields)
+		if fields.Summary == "" {
+			fmt.Fprintf(os.Stderr, "Row %d: summary: is empty\n", n)
+			res.failed++
+			continue
+		}
+
+		if c.CreateIssue.Exec != "" {
+			hooked := &jira.Issue{Fields: fields}
+			if err := applyExecHook(c.CreateIssue.Exec, hooked); err != nil {
+				fmt.Fprintf(os.Stderr, "Row %d: exec hook failed, sending the row as is: %v\n", n, err)
+			}
+			fields = hooked.Fields
+		}
+		issue, _, err := creator.Create(&jira.Issue{Fields: fields})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Row %d: %v\n", n, describeError(err, metaFields(metaType)))
+			res.failed++
+			continue
+		}
+		issue.Fields = fields
+		res.created = append(res.created, issue)
+		if err := l.record(key, issue.Key); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not record %s in %s: %v\n", issue.Key, l.path, err)
+		}
+	}
+	return res, nil
+}
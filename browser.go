@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// openURL opens url in the default browser.
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// browseURL is the link a person would open to look at an issue, as opposed
+// to the REST resource in the issue's Self field.
+func browseURL(c Config, key string) string {
+	return strings.TrimSuffix(c.JiraUrl, "/") + "/browse/" + key
+}
+
+const (
+	linkStyleSelf   = "self"
+	linkStyleBrowse = "browse"
+	linkStyleKey    = "key"
+)
+
+func validateLinkStyle(style string) error {
+	switch style {
+	case "", linkStyleSelf, linkStyleBrowse, linkStyleKey:
+		return nil
+	}
+	return fmt.Errorf("output.link_style must be %s, %s or %s, got %q", linkStyleSelf, linkStyleBrowse, linkStyleKey, style)
+}
+
+// issueLine is the line printed for a created issue.
+func issueLine(c Config, issue *jira.Issue) string {
+	switch c.Output.LinkStyle {
+	case linkStyleBrowse:
+		return fmt.Sprintf("%s: %s", issue.Key, browseURL(c, issue.Key))
+	case linkStyleKey:
+		return issue.Key
+	default:
+		return fmt.Sprintf("%s: %v", issue.Key, issue.Self)
+	}
+}
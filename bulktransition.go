@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/charmbracelet/huh"
+)
+
+// searchKeys returns the keys of every issue matching jql. All pages are
+// read before anything is changed, as changing the issues can move them in
+// and out of the results.
+func searchKeys(client *jira.Client, jql string) ([]string, error) {
+	var keys []string
+	err := client.Issue.SearchPages(jql, &jira.SearchOptions{MaxResults: 100, Fields: []string{"status"}}, func(issue jira.Issue) error {
+		keys = append(keys, issue.Key)
+		return nil
+	})
+	return keys, err
+}
+
+// runTransitions moves every issue matching jql through the transition or
+// to the status named to, after confirming how many issues that is, see
+// confirmBulk. Issues without such a transition from their current status
+// are reported, skipped and counted in the number returned. A resolution the
+// transition requires is only asked for when running interactively, without
+// -yes, and cancelling that prompt stops the run.
+func runTransitions(c Config, client *jira.Client, jql, to, resolution string) (int, error) {
+	keys, err := searchKeys(client, jql)
+	if err != nil {
+		return 0, fmt.Errorf("search failed: %w", err)
+	}
+	if len(keys) == 0 {
+		fmt.Println("No issues match")
+		return 0, nil
+	}
+
+	proceed, err := confirmBulk(c, fmt.Sprintf("Transition %d issues to %s", len(keys), to), len(keys), true)
+	if err != nil || !proceed {
+		return 0, err
+	}
+
+	failed, transitioned := 0, 0
+	for _, key := range keys {
+		if resolution, err = transitionIssue(client, key, to, resolution, !c.AssumeYes); err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				fmt.Fprintf(os.Stderr, "Transitioned %d, failed %d, stopped at %s\n", transitioned, failed, key)
+				return failed, err
+			}
+			fmt.Fprintf(os.Stderr, "Could not transition %s: %v\n", key, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Transitioned %s\n", key)
+		transitioned++
+	}
+	fmt.Fprintf(os.Stderr, "Transitioned %d, failed %d\n", transitioned, failed)
+	return failed, nil
+}
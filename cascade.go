@@ -0,0 +1,74 @@
+package main
+
+import (
+	"github.com/charmbracelet/huh"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// cascadingSelectType is the schema type of cascading selects, where the
+// options of the second select depend on what is picked in the first.
+const cascadingSelectType = "com.atlassian.jira.plugin.system.customfieldtypes:cascadingselect"
+
+// cascadeChildren reads the child options of each parent option of a
+// cascading select, keyed by the parent id.
+func cascadeChildren(values []interface{}) map[string][]allowedValue {
+	children := map[string][]allowedValue{}
+	for _, v := range values {
+		parent, ok := toAllowedValue(v)
+		if !ok {
+			continue
+		}
+		m, err := tcontainer.ConvertToMarshalMap(v, nil)
+		if err != nil {
+			continue
+		}
+		list, err := m.Array("children")
+		if err != nil {
+			continue
+		}
+		for _, c := range list {
+			if child, ok := toAllowedValue(c); ok {
+				children[parent.id] = append(children[parent.id], child)
+			}
+		}
+	}
+	return children
+}
+
+// childGroups returns a page with the child select for each parent option
+// that has children, shown only when that parent is picked.
+func (f *formField) childGroups() []*huh.Group {
+	var groups []*huh.Group
+	for _, parent := range f.allowed {
+		children := f.children[parent.id]
+		if len(children) == 0 {
+			continue
+		}
+		options := []huh.Option[string]{huh.NewOption("None", "")}
+		for _, c := range children {
+			options = append(options, huh.NewOption(c.label, c.id))
+		}
+		s := huh.NewSelect[string]().
+			Title(f.name + " › " + parent.label + ":").
+			Options(options...).
+			Value(&f.child)
+		if len(options) > 8 {
+			s = s.Description("/ to filter").Height(10)
+		}
+		id := parent.id
+		groups = append(groups, huh.NewGroup(s).WithHideFunc(func() bool { return f.value != id }))
+	}
+	return groups
+}
+
+// cascadePayload is the value of a cascading select, with the child option
+// when one of the picked parent's was chosen.
+func (f *formField) cascadePayload(parent string) map[string]interface{} {
+	payload := map[string]interface{}{"id": parent}
+	for _, c := range f.children[parent] {
+		if c.id == f.child {
+			payload["child"] = map[string]string{"id": c.id}
+		}
+	}
+	return payload
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newClient(c Config) (*jira.Client, error) {
+	tp := jira.BasicAuthTransport{Username: c.Username, Password: c.ApiKey}
+	if len(c.Headers) > 0 {
+		tp.Transport = headerTransport{headers: c.Headers, base: http.DefaultTransport}
+		debugf("adding headers to every request: %s", strings.Join(headerNames(c.Headers), ", "))
+	}
+	httpClient := tp.Client()
+	timeout, err := c.timeout()
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Timeout = timeout
+	return jira.NewClient(httpClient, c.JiraUrl)
+}
+
+// headerTransport sets the headers config on every request, for instances
+// behind a gateway or auth proxy asking for a key of its own.
+type headerTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// headerNames lists the configured headers by name, sorted, as their values
+// are often secrets.
+func headerNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// timeout parses the timeout setting. Zero, the default, means no timeout.
+func (c Config) timeout() (time.Duration, error) {
+	if c.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.Timeout)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("timeout %q is not a duration like 30s or 2m", c.Timeout)
+	}
+	return d, nil
+}
+
+// doRequestWithContext calls a REST endpoint go-jira has no wrapper for. The
+// response is decoded into v unless v is nil, and failures carry JIRA's error
+// messages.
+func doRequestWithContext(ctx context.Context, client *jira.Client, method, endpoint string, body, v interface{}) (*jira.Response, error) {
+	req, err := client.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req, v)
+	if err != nil {
+		return resp, jira.NewJiraError(resp, err)
+	}
+	if v == nil {
+		resp.Body.Close()
+	}
+	return resp, nil
+}
+
+// doRequest wraps doRequestWithContext using the background context.
+func doRequest(client *jira.Client, method, endpoint string, body, v interface{}) (*jira.Response, error) {
+	return doRequestWithContext(context.Background(), client, method, endpoint, body, v)
+}
+
+// IssueCreator is the part of the JIRA API used to create issues.
+// *jira.IssueService implements it, tests can pass a fake instead.
+type IssueCreator interface {
+	Create(issue *jira.Issue) (*jira.Issue, *jira.Response, error)
+}
+
+type issueCreatedMsg struct {
+	issue *jira.Issue
+}
+
+type issueFailedMsg struct {
+	err error
+	// unauthorized is set when JIRA rejected the credentials, which usually
+	// means the API token expired or was revoked.
+	unauthorized bool
+	// transient is set when the request may well go through if sent again:
+	// it never got an answer, or JIRA failed on its end.
+	transient bool
+}
+
+// failedMsg describes a create that failed with err, resp being JIRA's
+// answer if there was one.
+func failedMsg(resp *jira.Response, err error) issueFailedMsg {
+	msg := issueFailedMsg{err: err}
+	switch {
+	case resp == nil:
+		// Only a request that never got an answer is worth sending again,
+		// not one that failed before it went out.
+		var netErr net.Error
+		var urlErr *url.Error
+		msg.transient = errors.As(err, &netErr) || errors.As(err, &urlErr)
+	case resp.StatusCode == http.StatusUnauthorized:
+		msg.unauthorized = true
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		msg.transient = true
+	}
+	return msg
+}
+
+// createIssue sends the create request in the background and reports back
+// with either an issueCreatedMsg or an issueFailedMsg.
+func createIssue(creator IssueCreator, issue *jira.Issue) tea.Cmd {
+	return func() tea.Msg {
+		created, resp, err := creator.Create(issue)
+		if err != nil {
+			return failedMsg(resp, err)
+		}
+		return issueCreatedMsg{issue: created}
+	}
+}
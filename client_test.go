@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// fakeCreator answers creates without a JIRA, recording what was sent.
+type fakeCreator struct {
+	sent    []*jira.Issue
+	created *jira.Issue
+	status  int
+	err     error
+}
+
+func (f *fakeCreator) Create(issue *jira.Issue) (*jira.Issue, *jira.Response, error) {
+	f.sent = append(f.sent, issue)
+	var resp *jira.Response
+	if f.status != 0 {
+		resp = &jira.Response{Response: &http.Response{StatusCode: f.status}}
+	}
+	if f.err != nil {
+		return nil, resp, f.err
+	}
+	return f.created, resp, nil
+}
+
+func TestCreateIssueCreated(t *testing.T) {
+	fake := &fakeCreator{created: &jira.Issue{Key: "OPS-1"}, status: http.StatusCreated}
+	issue := &jira.Issue{Fields: &jira.IssueFields{Summary: "Broken"}}
+
+	msg := createIssue(fake, issue)()
+
+	created, ok := msg.(issueCreatedMsg)
+	if !ok {
+		t.Fatalf("got %T, want issueCreatedMsg", msg)
+	}
+	if created.issue.Key != "OPS-1" {
+		t.Errorf("key = %q, want OPS-1", created.issue.Key)
+	}
+	if len(fake.sent) != 1 || fake.sent[0] != issue {
+		t.Errorf("sent %v, want the issue once", fake.sent)
+	}
+}
+
+func TestCreateIssueFailed(t *testing.T) {
+	for _, tc := range []struct {
+		name                    string
+		status                  int
+		err                     error
+		unauthorized, transient bool
+	}{
+		{"no response", 0, &url.Error{Op: "Post", URL: "https://jira.example.com", Err: errors.New("connection reset")}, false, true},
+		{"not sent", 0, errors.New("no request type"), false, false},
+		{"bad request", http.StatusBadRequest, errors.New("create failed"), false, false},
+		{"unauthorized", http.StatusUnauthorized, errors.New("create failed"), true, false},
+		{"rate limited", http.StatusTooManyRequests, errors.New("create failed"), false, true},
+		{"server error", http.StatusBadGateway, errors.New("create failed"), false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want := tc.err
+			fake := &fakeCreator{status: tc.status, err: want}
+
+			msg := createIssue(fake, &jira.Issue{Fields: &jira.IssueFields{}})()
+
+			failed, ok := msg.(issueFailedMsg)
+			if !ok {
+				t.Fatalf("got %T, want issueFailedMsg", msg)
+			}
+			if failed.err != want {
+				t.Errorf("err = %v, want %v", failed.err, want)
+			}
+			if failed.unauthorized != tc.unauthorized || failed.transient != tc.transient {
+				t.Errorf("unauthorized, transient = %v, %v, want %v, %v", failed.unauthorized, failed.transient, tc.unauthorized, tc.transient)
+			}
+		})
+	}
+}
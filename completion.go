@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// subcommands are the words accepted in place of flags as the first
+// argument.
+var subcommands = append([]string{"init", "login", "batch", "create", "edit", "transition", "config", "completion"}, listCommands...)
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// writeCompletion writes a completion script for shell covering the
+// subcommands and every flag registered on fs.
+func writeCompletion(w io.Writer, shell string, fs *flag.FlagSet) error {
+	var flags []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) { flags = append(flags, f) })
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, flags)
+	case "zsh":
+		writeZshCompletion(w, flags)
+	case "fish":
+		writeFishCompletion(w, flags)
+	default:
+		return fmt.Errorf("unsupported shell %q, expected one of: %s", shell, strings.Join(completionShells, ", "))
+	}
+	return nil
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+var completionEscaper = strings.NewReplacer("'", "", "[", "(", "]", ")", ":", " ", "\n", " ")
+
+// flagUsage is the flag's help text made safe for single quoted strings and
+// zsh argument specs.
+func flagUsage(f *flag.Flag) string {
+	_, usage := flag.UnquoteUsage(f)
+	return completionEscaper.Replace(usage)
+}
+
+func writeBashCompletion(w io.Writer, flags []*flag.Flag) {
+	names := make([]string, len(flags))
+	for i, f := range flags {
+		names[i] = "-" + f.Name
+	}
+
+	fmt.Fprintf(w, `_lazyjira() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    if [[ ${COMP_WORDS[1]} == completion && $COMP_CWORD -eq 2 ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+        return
+    fi
+    if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+        return
+    fi
+    COMPREPLY=($(compgen -W "%s" -- "$cur"))
+}
+complete -o default -F _lazyjira lazyjira
+`, strings.Join(completionShells, " "), strings.Join(subcommands, " "), strings.Join(names, " "))
+}
+
+func writeZshCompletion(w io.Writer, flags []*flag.Flag) {
+	fmt.Fprintln(w, "#compdef lazyjira")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "_lazyjira() {")
+	fmt.Fprintln(w, "    if (( CURRENT == 3 )) && [[ ${words[2]} == completion ]]; then")
+	fmt.Fprintf(w, "        _values 'shell' %s\n", strings.Join(completionShells, " "))
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, "    _arguments \\")
+	for _, f := range flags {
+		if isBoolFlag(f) {
+			fmt.Fprintf(w, "        '-%s[%s]' \\\n", f.Name, flagUsage(f))
+		} else {
+			name, _ := flag.UnquoteUsage(f)
+			if name == "" {
+				name = "value"
+			}
+			fmt.Fprintf(w, "        '-%s[%s]:%s:' \\\n", f.Name, flagUsage(f), completionEscaper.Replace(name))
+		}
+	}
+	fmt.Fprintf(w, "        '1::command:(%s)'\n", strings.Join(subcommands, " "))
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `_lazyjira "$@"`)
+}
+
+func writeFishCompletion(w io.Writer, flags []*flag.Flag) {
+	fmt.Fprintf(w, "complete -c lazyjira -n '__fish_use_subcommand' -f -a '%s'\n", strings.Join(subcommands, " "))
+	fmt.Fprintf(w, "complete -c lazyjira -n '__fish_seen_subcommand_from completion' -f -a '%s'\n", strings.Join(completionShells, " "))
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c lazyjira -o %s -d '%s'", f.Name, flagUsage(f))
+		if !isBoolFlag(f) {
+			line += " -r"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
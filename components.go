@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// useComponentInput adds a free text input to the components field, which
+// is a plain input already when the project has no components yet.
+// Components typed there that the project does not have yet are created
+// when create is set, and rejected otherwise.
+func useComponentInput(f *formField, create bool) {
+	f.createMissing = create
+	if len(f.allowed) > 0 {
+		f.allowCustom = true
+	}
+}
+
+// validateCustom checks the values typed next to the options of f. Only a
+// components field that may not create components is checked.
+func (f *formField) validateCustom(s string) error {
+	if f.id != "components" || f.createMissing {
+		return nil
+	}
+	for _, name := range splitList(s) {
+		if _, ok := f.allowedByLabel(name); !ok {
+			labels := make([]string, len(f.allowed))
+			for i, v := range f.allowed {
+				labels[i] = v.label
+			}
+			return fmt.Errorf("no component %q, pick one of: %s", name, strings.Join(labels, ", "))
+		}
+	}
+	return nil
+}
+
+func (f *formField) allowedByLabel(label string) (allowedValue, bool) {
+	for _, v := range f.allowed {
+		if strings.EqualFold(v.label, label) {
+			return v, true
+		}
+	}
+	return allowedValue{}, false
+}
+
+// customRef is the payload for a value typed next to the options: the
+// option with that label, or the value by name.
+func (f *formField) customRef(name string) map[string]string {
+	if v, ok := f.allowedByLabel(name); ok {
+		return f.ref(v.id)
+	}
+	return map[string]string{"name": name}
+}
+
+// missingComponents lists the components typed on the form that are to be
+// created before the issue.
+func missingComponents(fields []*formField) []string {
+	var names []string
+	for _, f := range fields {
+		if f.id != "components" || !f.createMissing {
+			continue
+		}
+		typed := f.custom
+		if len(f.allowed) == 0 {
+			typed = f.value
+		}
+		for _, name := range splitList(typed) {
+			if _, ok := f.allowedByLabel(name); !ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// createComponents adds the named components to the project ahead of the
+// create, reporting any that could not be added on the success screen.
+func createComponents(client *jira.Client, project string, names []string) tea.Cmd {
+	return func() tea.Msg {
+		var failed []string
+		for _, name := range names {
+			if _, _, err := client.Component.Create(&jira.CreateComponentOptions{Name: name, Project: project}); err != nil {
+				failed = append(failed, fmt.Sprintf("%s (%v)", name, err))
+			}
+		}
+		if len(failed) > 0 {
+			return noticeMsg("Could not create components: " + strings.Join(failed, ", "))
+		}
+		return nil
+	}
+}
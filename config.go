@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/trivago/tgo/tcontainer"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	JiraUrl     string            `yaml:"jira_url"`
+	Username    string            `yaml:"username"`
+	ApiKey      string            `yaml:"api_key"`
+	Timeout     string            `yaml:"timeout"`
+	CreateIssue CreateIssueConfig `yaml:"create_issue"`
+	OnSuccess   OnSuccessConfig   `yaml:"on_success"`
+	Output      OutputConfig      `yaml:"output"`
+	UI          UIConfig          `yaml:"ui"`
+	// Keybindings maps TUI actions to the keys replacing their defaults.
+	Keybindings map[string][]string `yaml:"keybindings"`
+	// RepoProjects maps git remote url patterns to project keys, picking the
+	// project when lazyjira runs inside a matching repo.
+	RepoProjects map[string]string `yaml:"repo_projects"`
+	// FieldAliases maps friendly names to the field ids they stand for,
+	// usable wherever a field id is.
+	FieldAliases map[string]string `yaml:"field_aliases"`
+	// Headers are sent along with every request to JIRA.
+	Headers map[string]string `yaml:"headers"`
+	// ConfirmThreshold is how many issues a bulk operation may touch before
+	// "yes" has to be typed to go ahead.
+	ConfirmThreshold int `yaml:"confirm_threshold"`
+	// TokenExpires is the date the API token expires on. The TUI warns about
+	// it from TokenWarnDays before.
+	TokenExpires  string `yaml:"token_expires"`
+	TokenWarnDays int    `yaml:"token_warn_days"`
+	// AssumeYes skips the confirmations before bulk operations. It is set by
+	// -yes, or -interactive=false, never by the config file.
+	AssumeYes bool `yaml:"-"`
+}
+
+type OutputConfig struct {
+	// LinkStyle picks what is printed after the key of a created issue:
+	// self (the REST url, the default), browse (the web url) or key (the key
+	// alone).
+	LinkStyle string `yaml:"link_style"`
+}
+
+type CreateIssueConfig struct {
+	Project            string                `yaml:"project"`
+	DefaultType        string                `yaml:"default_type"`
+	DefaultSeverity    string                `yaml:"default_severity"`
+	DefaultSubtaskType string                `yaml:"default_subtask_type"`
+	DefaultRequestType string                `yaml:"default_request_type"`
+	AllowedTypes       []string              `yaml:"allowed_types"`
+	TypeOrder          []string              `yaml:"type_order"`
+	Rank               string                `yaml:"rank"`
+	LabelOptions       []string              `yaml:"label_options"`
+	CustomLabels       bool                  `yaml:"custom_labels"`
+	AutoWatch          *bool                 `yaml:"auto_watch"`
+	AutoVote           bool                  `yaml:"auto_vote"`
+	Transition         string                `yaml:"transition"`
+	Resolution         string                `yaml:"resolution"`
+	CustomFields       tcontainer.MarshalMap `yaml:"custom_fields"`
+	Exec               string                `yaml:"exec"`
+	AutoApprove        bool                  `yaml:"auto_approve"`
+	EpicColor          string                `yaml:"epic_color"`
+	CreateComponents   bool                  `yaml:"auto_create_components"`
+	UpdateHistory      *bool                 `yaml:"update_history"`
+	LabelTransform     string                `yaml:"label_transform"`
+}
+
+// defaultConfigPath is lazyjira/config.yaml in the user config directory,
+// %AppData% on Windows for instance, or config.toml next to it when only
+// that exists. A config in ~/.config, where earlier versions kept it on
+// every platform, is still picked up.
+func defaultConfigPath() (string, error) {
+	if home, err := os.UserHomeDir(); err == nil {
+		legacy := filepath.Join(home, ".config", "lazyjira", "config.yaml")
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy, nil
+		}
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "lazyjira", "config.yaml")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if tomlPath := filepath.Join(dir, "lazyjira", "config.toml"); fileExists(tomlPath) {
+			return tomlPath, nil
+		}
+	}
+	return path, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// isTOML reports whether the config file at path is TOML rather than YAML,
+// going by its extension.
+func isTOML(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// loadConfig reads the config file at path. Files listed under a top level
+// include key are merged in first, in order, so that the including file
+// wins on any conflicting keys.
+func loadConfig(path string) (Config, error) {
+	var c Config
+
+	raw, err := readConfigTree(path, map[string]bool{})
+	if err != nil {
+		return c, err
+	}
+
+	b, err := yaml.Marshal(raw)
+	if err != nil {
+		return c, err
+	}
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	c.cleanCredentials(path)
+	return c, nil
+}
+
+// validate checks the settings that can be wrong without failing to parse.
+func (c Config) validate() error {
+	if _, err := c.timeout(); err != nil {
+		return err
+	}
+	if err := validateRank(c.CreateIssue.Rank); err != nil {
+		return err
+	}
+	if err := validateActions(c.OnSuccess.Actions); err != nil {
+		return err
+	}
+	if err := validateLinkStyle(c.Output.LinkStyle); err != nil {
+		return err
+	}
+	if err := validateTokenExpires(c.TokenExpires); err != nil {
+		return err
+	}
+	if err := validateLabelTransform(c.CreateIssue.LabelTransform); err != nil {
+		return err
+	}
+	if err := validateFieldAliases(c.FieldAliases); err != nil {
+		return err
+	}
+	_, err := newKeyMap(c.Keybindings)
+	return err
+}
+
+// cleanCredentials tidies up the connection settings, which are often pasted
+// in along with a line break or the quotes around them. Either makes JIRA
+// reject the login without saying why.
+func (c *Config) cleanCredentials(source string) {
+	for _, v := range []struct {
+		key   string
+		value *string
+	}{
+		{"jira_url", &c.JiraUrl},
+		{"username", &c.Username},
+		{"api_key", &c.ApiKey},
+	} {
+		if cleaned, changed := cleanValue(*v.value); changed {
+			*v.value = cleaned
+			debugf("%s: trimmed whitespace or quotes around %s", source, v.key)
+		}
+	}
+}
+
+// cleanValue trims whitespace and one pair of wrapping quotes from s, and
+// reports whether there was anything to remove.
+func cleanValue(s string) (string, bool) {
+	cleaned := strings.TrimSpace(s)
+	if len(cleaned) >= 2 {
+		if q := cleaned[0]; (q == '"' || q == '\'') && cleaned[len(cleaned)-1] == q {
+			cleaned = strings.TrimSpace(cleaned[1 : len(cleaned)-1])
+		}
+	}
+	return cleaned, cleaned != s
+}
+
+// debugEnv turns on notes about what lazyjira does behind the scenes.
+const debugEnv = "LAZYJIRA_DEBUG"
+
+// debugf prints a note to stderr when LAZYJIRA_DEBUG is set.
+func debugf(format string, args ...interface{}) {
+	if os.Getenv(debugEnv) != "" {
+		fmt.Fprintf(os.Stderr, "debug: "+format+"\n", args...)
+	}
+}
+
+// readConfigTree reads a single config file and everything it includes into
+// one map. seen guards against include cycles.
+func readConfigTree(path string, seen map[string]bool) (map[string]interface{}, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if seen[abs] {
+		return nil, fmt.Errorf("%s: include cycle", path)
+	}
+	seen[abs] = true
+	defer delete(seen, abs)
+
+	f, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if isTOML(path) {
+		err = toml.Unmarshal(f, &doc)
+	} else {
+		err = yaml.Unmarshal(f, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+
+	includes, err := includePaths(doc["include"])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	delete(doc, "include")
+
+	merged := map[string]interface{}{}
+	for _, include := range includes {
+		include = expandHome(include)
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		sub, err := readConfigTree(include, seen)
+		if err != nil {
+			return nil, err
+		}
+		mergeConfig(merged, sub)
+	}
+	mergeConfig(merged, doc)
+	return merged, nil
+}
+
+func includePaths(v interface{}) ([]string, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		paths := make([]string, 0, len(v))
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("include entries must be paths, got %v", p)
+			}
+			paths = append(paths, s)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("include must be a list of paths")
+	}
+}
+
+// mergeConfig merges src into dst. Nested maps are merged key by key, any
+// other value in src replaces the one in dst.
+func mergeConfig(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sm, ok := v.(map[string]interface{}); ok {
+			if dm, ok := dst[k].(map[string]interface{}); ok {
+				mergeConfig(dm, sm)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/charmbracelet/huh"
+)
+
+// configTemplate starts off a config file that does not exist yet.
+const configTemplate = `# lazyjira config, lazyjira init fills in the connection settings for you.
+jira_url: https://example.atlassian.net
+username: you@example.com
+api_key: ""
+
+create_issue:
+  project: ""
+  # default_type: Task
+`
+
+// runConfigEdit opens the config file at path in the user's editor,
+// starting from configTemplate when there is none yet. The edited file is
+// checked, and a broken one is opened again or put back as it was.
+func runConfigEdit(path string) error {
+	if isConfigURL(path) {
+		return errors.New("remote configs cannot be edited, edit the file at its source")
+	}
+	original, err := os.ReadFile(path)
+	existed := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if !existed {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+		template := []byte(configTemplate)
+		if isTOML(path) {
+			template = nil
+		}
+		if err := os.WriteFile(path, template, 0o600); err != nil {
+			return err
+		}
+	}
+
+	for {
+		args := append(editorCommand(), path)
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("editor: %w", err)
+		}
+
+		c, err := loadConfig(path)
+		if err == nil {
+			err = c.validate()
+		}
+		if err == nil {
+			fmt.Printf("Config at %s is valid\n", path)
+			return nil
+		}
+
+		fmt.Fprintln(os.Stderr, "The config has a problem:", err)
+		again := true
+		if err := huh.NewForm(huh.NewGroup(
+			huh.NewConfirm().
+				Title("Edit it again?").
+				Affirmative("Edit").
+				Negative("Undo my changes").
+				Value(&again),
+		)).Run(); err != nil {
+			return err
+		}
+		if again {
+			continue
+		}
+		if !existed {
+			return os.Remove(path)
+		}
+		return os.WriteFile(path, original, 0o600)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+)
+
+// defaultConfirmThreshold is how many issues a bulk operation may touch
+// before "yes" has to be typed, unless confirm_threshold says otherwise.
+const defaultConfirmThreshold = 20
+
+func (c Config) confirmThreshold() int {
+	if c.ConfirmThreshold > 0 {
+		return c.ConfirmThreshold
+	}
+	return defaultConfirmThreshold
+}
+
+// confirmBulk asks before an operation touching n issues, described by what
+// as in "Transition 12 issues to Done". From the confirm_threshold on, "yes"
+// has to be typed out. Below it a plain yes/no is asked when ask is set, and
+// nothing otherwise. With -yes nothing is asked at all.
+func confirmBulk(c Config, what string, n int, ask bool) (bool, error) {
+	if c.AssumeYes {
+		return true, nil
+	}
+	if n < c.confirmThreshold() {
+		if !ask {
+			return true, nil
+		}
+		proceed := false
+		err := huh.NewForm(huh.NewGroup(
+			huh.NewConfirm().
+				Title(what + "?").
+				Value(&proceed),
+		)).Run()
+		return proceed, err
+	}
+
+	var typed string
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewInput().
+			Title(what + "?").
+			Description(fmt.Sprintf("That is %d issues, type yes to go ahead", n)).
+			Value(&typed),
+	)).Run()
+	return typed == "yes", err
+}
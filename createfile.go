@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// readIssueFile reads an issue from a JSON file holding either a whole
+// create payload or just its fields object.
+func readIssueFile(path string) (*jira.Issue, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, ok := probe["fields"]; !ok {
+		b = append(append([]byte(`{"fields":`), b...), '}')
+	}
+
+	var issue jira.Issue
+	if err := json.Unmarshal(b, &issue); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if issue.Fields == nil {
+		return nil, fmt.Errorf("%s: no fields", path)
+	}
+	return &issue, nil
+}
+
+// setFields lists the fields an issue sends, known and custom ones alike.
+func setFields(fields *jira.IssueFields) (map[string]bool, error) {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var sent map[string]json.RawMessage
+	if err := json.Unmarshal(b, &sent); err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(sent))
+	for k, v := range sent {
+		set[k] = string(v) != "null" && string(v) != `""` && string(v) != "{}"
+	}
+	return set, nil
+}
+
+// createFromFile creates the issue in a JSON file as it is, without the
+// form. The project and issue type fall back to the config and may be given
+// by id, -field flags override what the file sets, the
+// create_issue.custom_fields fill in fields the file leaves unset, labels
+// from both are merged, and required fields still missing after that are an
+// error.
+func createFromFile(client *jira.Client, creator IssueCreator, c CreateIssueConfig, path, issueType string, fieldArgs []fieldArg) (*jira.Issue, error) {
+	issue, err := readIssueFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fields := issue.Fields
+
+	project := fields.Project.Key
+	switch {
+	case project != "":
+	case fields.Project.ID != "":
+		// Create metadata is looked up by key.
+		p, _, err := client.Project.Get(fields.Project.ID)
+		if err != nil {
+			return nil, fmt.Errorf("project %s: %w", fields.Project.ID, err)
+		}
+		project = p.Key
+	case c.Project != "":
+		project = c.Project
+	default:
+		return nil, errors.New("no project in the file, use -project or set create_issue.project")
+	}
+	metaProject, degraded, err := loadProjectMeta(context.Background(), client, project, c)
+	if err != nil {
+		return nil, err
+	}
+	if degraded {
+		fmt.Fprintln(os.Stderr, "Warning:", createMetaForbidden)
+	}
+	var metaType *jira.MetaIssueType
+	if fields.Type.Name == "" && fields.Type.ID != "" && degraded {
+		// The stand-in types have no ids to match, send the id as given.
+		metaType = &jira.MetaIssueType{Id: fields.Type.ID}
+	} else if fields.Type.Name == "" && fields.Type.ID != "" {
+		if metaType, err = issueTypeByID(metaProject, fields.Type.ID, c); err != nil {
+			return nil, err
+		}
+	} else {
+		if fields.Type.Name != "" {
+			issueType = fields.Type.Name
+		}
+		if metaType, err = pickIssueType(metaProject, issueType, c); err != nil {
+			return nil, err
+		}
+	}
+	fields.Project = jira.Project{Key: metaProject.Key}
+	fields.Type = jira.IssueType{ID: metaType.Id, Name: metaType.Name}
+
+	if fields.Unknowns == nil {
+		fields.Unknowns = tcontainer.NewMarshalMap()
+	}
+	for k, v := range c.CustomFields {
+		if _, ok := fields.Unknowns[k]; !ok && k != "labels" {
+			fields.Unknowns[k] = v
+		}
+	}
+	extra, err := resolveFieldArgs(fieldArgs, metaType)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range extra {
+		fields.Unknowns[k] = v
+	}
+	mergeLabels(fields, labelList(c.CustomFields["labels"]))
+
+	set, err := setFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, f := range metaFields(metaType) {
+		if f.required && !set[f.id] && f.id != "project" && f.id != "issuetype" {
+			missing = append(missing, fmt.Sprintf("%s (%s)", f.name, f.id))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s: missing required fields %s", path, strings.Join(missing, ", "))
+	}
+
+	c.transformLabels(fields)
+	created, _, err := creator.Create(issue)
+	if err != nil {
+		return nil, describeError(err, metaFields(metaType))
+	}
+	created.Fields = fields
+	return created, nil
+}
+
+// issueTypeByID picks the issue type with the given id from the create
+// metadata of a project.
+func issueTypeByID(metaProject *jira.MetaProject, id string, c CreateIssueConfig) (*jira.MetaIssueType, error) {
+	for _, t := range metaProject.IssueTypes {
+		if t != nil && t.Id == id {
+			if err := checkParent(t, metaProject.Key, c); err != nil {
+				return nil, err
+			}
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("project %s has no issue type with id %s", metaProject.Key, id)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+const createMetaForbidden = "Create metadata is not available to you, only summary and description can be set"
+
+// loadProjectMeta fetches the create metadata of a project. Instances that
+// refuse create metadata get a basic stand-in instead, and degraded is set.
+func loadProjectMeta(ctx context.Context, client *jira.Client, key string, c CreateIssueConfig) (project *jira.MetaProject, degraded bool, err error) {
+	meta, resp, err := client.Issue.GetCreateMetaWithContext(ctx, key)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusForbidden {
+			return fallbackProject(key, c), true, nil
+		}
+		return nil, false, err
+	}
+	project, err = findProject(meta, key)
+	return project, false, err
+}
+
+// fallbackProject stands in for the create metadata of a project. Its issue
+// types, taken from config or a few common ones, list no fields, which leaves
+// the form with summary and description.
+func fallbackProject(key string, c CreateIssueConfig) *jira.MetaProject {
+	names := c.AllowedTypes
+	if len(names) == 0 {
+		names = []string{c.defaultType()}
+		for _, n := range []string{"Task", "Story", "Bug"} {
+			if !containsFold(names, n) {
+				names = append(names, n)
+			}
+		}
+	}
+	project := &jira.MetaProject{Key: key}
+	for _, n := range names {
+		project.IssueTypes = append(project.IssueTypes, &jira.MetaIssueType{Name: n})
+	}
+	if c.DefaultSubtaskType != "" {
+		project.IssueTypes = append(project.IssueTypes, &jira.MetaIssueType{Name: c.DefaultSubtaskType, Subtasks: true})
+	}
+	return project
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// writeCurl prints a curl command sending the same create request lazyjira
+// would, for trying a payload out by hand. The credentials and the values of
+// configured headers are left out.
+func writeCurl(w io.Writer, c Config, issue *jira.Issue) error {
+	body, err := json.MarshalIndent(issue, "", "  ")
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimSuffix(c.JiraUrl, "/") + "/" + c.CreateIssue.createEndpoint()
+	var headers strings.Builder
+	for _, name := range headerNames(c.Headers) {
+		fmt.Fprintf(&headers, "  -H %s \\\n", shellQuote(name+": REDACTED"))
+	}
+	_, err = fmt.Fprintf(w, "curl -X POST %s \\\n  -H 'Content-Type: application/json' \\\n  -H 'Authorization: Basic REDACTED' \\\n%s  --data %s\n",
+		shellQuote(endpoint), headers.String(), shellQuote(string(body)))
+	return err
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
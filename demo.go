@@ -0,0 +1,88 @@
+package main
+
+import (
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// demoNotice is printed when a -preview session completes the form.
+const demoNotice = "(preview — no issue created)"
+
+// demoProjects stand in for the project list in -preview mode.
+var demoProjects = jira.ProjectList{
+	{Key: "DEMO", Name: "Demo project"},
+	{Key: "OPS", Name: "Operations"},
+}
+
+// demoProject is create metadata for -preview mode, with one field of each
+// kind the form knows how to render.
+func demoProject(key string) *jira.MetaProject {
+	option := func(values ...string) []interface{} {
+		var out []interface{}
+		for i, v := range values {
+			out = append(out, map[string]interface{}{"id": string(rune('1' + i)), "name": v})
+		}
+		return out
+	}
+	fields, _ := tcontainer.ConvertToMarshalMap(map[string]interface{}{
+		"summary":     map[string]interface{}{"name": "Summary", "required": true, "schema": map[string]interface{}{"type": "string", "system": "summary"}},
+		"description": map[string]interface{}{"name": "Description", "schema": map[string]interface{}{"type": "string", "system": "description"}},
+		"priority": map[string]interface{}{
+			"name": "Priority", "required": true,
+			"schema":        map[string]interface{}{"type": "priority", "system": "priority"},
+			"allowedValues": option("Highest", "High", "Medium", "Low", "Lowest"),
+		},
+		"components": map[string]interface{}{
+			"name":          "Components",
+			"schema":        map[string]interface{}{"type": "array", "items": "component", "system": "components"},
+			"allowedValues": option("Backend", "Frontend", "Docs"),
+		},
+		"labels":            map[string]interface{}{"name": "Labels", "schema": map[string]interface{}{"type": "array", "items": "string", "system": "labels"}},
+		"duedate":           map[string]interface{}{"name": "Due date", "schema": map[string]interface{}{"type": "date", "system": "duedate"}},
+		"customfield_10016": map[string]interface{}{"name": "Story points", "schema": map[string]interface{}{"type": "number"}},
+		"customfield_10020": map[string]interface{}{
+			"name":   "Affected area",
+			"schema": map[string]interface{}{"type": "option-with-child", "custom": cascadingSelectType},
+			"allowedValues": []interface{}{
+				map[string]interface{}{"id": "10", "value": "Web", "children": []interface{}{map[string]interface{}{"id": "20", "value": "Checkout"}, map[string]interface{}{"id": "21", "value": "Search"}}},
+				map[string]interface{}{"id": "11", "value": "Mobile", "children": []interface{}{map[string]interface{}{"id": "22", "value": "iOS"}, map[string]interface{}{"id": "23", "value": "Android"}}},
+			},
+		},
+		"timetracking": map[string]interface{}{"name": "Time tracking", "schema": map[string]interface{}{"type": "timetracking", "system": "timetracking"}},
+	}, nil)
+
+	name := key
+	for _, p := range demoProjects {
+		if p.Key == key {
+			name = p.Name
+		}
+	}
+	return &jira.MetaProject{
+		Key:  key,
+		Name: name,
+		IssueTypes: []*jira.MetaIssueType{
+			{Id: "1", Name: "Bug", Fields: fields},
+			{Id: "2", Name: "Task", Fields: fields},
+			{Id: "3", Name: "Story", Fields: fields},
+		},
+	}
+}
+
+// loadProjectsCmd loads the projects for the picker, or hands out the demo
+// ones in -preview mode.
+func (m Model) loadProjectsCmd() tea.Cmd {
+	if m.demo {
+		return func() tea.Msg { return projectsLoadedMsg{demoProjects} }
+	}
+	return loadProjects(m.ctx, m.client)
+}
+
+// loadCreateMetaCmd is loadCreateMeta, or the demo metadata in -preview
+// mode.
+func (m Model) loadCreateMetaCmd(key string) tea.Cmd {
+	if m.demo {
+		return func() tea.Msg { return createMetaLoadedMsg{project: demoProject(key)} }
+	}
+	return loadCreateMeta(m.ctx, m.client, key, m.config.CreateIssue)
+}
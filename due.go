@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// relativeDue matches offsets like +7d or +2w.
+var relativeDue = regexp.MustCompile(`^\+?(\d+)([dw])$`)
+
+// parseDue resolves a -due value to a date. It takes a date like
+// 2024-01-31, today, tomorrow, an offset like +3d or +2w, or a weekday,
+// optionally after "next", which is the first such day after today.
+func parseDue(raw string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if t, err := time.ParseInLocation(dateLayout, s, now.Location()); err == nil {
+		return t, nil
+	}
+	switch s {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	if m := relativeDue.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "w" {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n), nil
+	}
+	day := strings.TrimPrefix(s, "next ")
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if day == strings.ToLower(d.String()) || day == strings.ToLower(d.String()[:3]) {
+			ahead := (int(d) - int(today.Weekday()) + 7) % 7
+			if ahead == 0 {
+				ahead = 7
+			}
+			return today.AddDate(0, 0, ahead), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("-due %q is not a date like 2024-01-31, +3d, +2w, tomorrow or friday", raw)
+}
+
+// setDue presets the due date of created issues, keeping it off the form.
+func setDue(c *CreateIssueConfig, due time.Time) {
+	if c.CustomFields == nil {
+		c.CustomFields = tcontainer.NewMarshalMap()
+	}
+	c.CustomFields["duedate"] = due.Format(dateLayout)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/charmbracelet/huh"
+)
+
+// editableFields are the fields the edit command offers, as they are shown
+// in its form.
+type editableFields struct {
+	summary     string
+	description string
+	labels      string
+}
+
+func currentFields(issue *jira.Issue) editableFields {
+	return editableFields{
+		summary:     issue.Fields.Summary,
+		description: issue.Fields.Description,
+		labels:      strings.Join(issue.Fields.Labels, ", "),
+	}
+}
+
+// splitLabels reads a comma or space separated list of labels, sorted so
+// that reordering them does not count as a change.
+func splitLabels(s string) []string {
+	labels := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	sort.Strings(labels)
+	return labels
+}
+
+// changedFields is the update payload for the fields that differ between
+// before and after. It is empty when nothing was changed.
+func changedFields(before, after editableFields) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if summary := strings.TrimSpace(after.summary); summary != strings.TrimSpace(before.summary) {
+		fields["summary"] = summary
+	}
+	if strings.TrimSpace(after.description) != strings.TrimSpace(before.description) {
+		fields["description"] = after.description
+	}
+	if labels := splitLabels(after.labels); strings.Join(labels, " ") != strings.Join(splitLabels(before.labels), " ") {
+		if labels == nil {
+			labels = []string{}
+		}
+		fields["labels"] = labels
+	}
+	return fields
+}
+
+// runEdit fetches an issue, lets its summary, description and labels be
+// edited in a form and sends only the fields that were changed.
+func runEdit(client *jira.Client, key string) error {
+	issue, _, err := client.Issue.Get(key, &jira.GetQueryOptions{Fields: "summary,description,labels"})
+	if err != nil {
+		return fmt.Errorf("could not fetch %s: %w", key, err)
+	}
+
+	before := currentFields(issue)
+	after := before
+	err = huh.NewForm(huh.NewGroup(
+		huh.NewInput().
+			Title("Summary:").
+			Value(&after.summary).
+			Validate(required("Summary")),
+		huh.NewText().
+			Title("Description:").
+			Value(&after.description),
+		huh.NewInput().
+			Title("Labels:").
+			Description("Separated by commas or spaces").
+			Value(&after.labels),
+	).Title("Editing " + issue.Key)).Run()
+	if err != nil {
+		return err
+	}
+
+	fields := changedFields(before, after)
+	if len(fields) == 0 {
+		fmt.Printf("Nothing changed on %s\n", issue.Key)
+		return nil
+	}
+	body := map[string]interface{}{"fields": fields}
+	if _, err := doRequest(client, "PUT", "rest/api/2/issue/"+issue.Key, body, nil); err != nil {
+		return fmt.Errorf("could not update %s: %w", issue.Key, err)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("Updated %s of %s\n", strings.Join(names, ", "), issue.Key)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// jsonEditedMsg carries the create payload back from the editor.
+type jsonEditedMsg struct {
+	text string
+	err  error
+}
+
+// editorCommand is the user's editor, from $VISUAL or $EDITOR, which may
+// carry arguments such as "code --wait".
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if args := strings.Fields(os.Getenv(env)); len(args) > 0 {
+			return args
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// payloadJSON is the create request for what is on the form right now.
+func (m Model) payloadJSON() (string, error) {
+	fields := *m.issue.Fields
+	fields.Unknowns = fields.Unknowns.Clone()
+	applyFormFields(m.fields, &fields)
+	b, err := json.MarshalIndent(&jira.Issue{Fields: &fields}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+// editJSON opens the create payload in the user's editor, or the last edit
+// when that could not be used.
+func (m Model) editJSON() tea.Cmd {
+	text := m.editedJSON
+	if text == "" {
+		var err error
+		if text, err = m.payloadJSON(); err != nil {
+			return func() tea.Msg { return jsonEditedMsg{err: err} }
+		}
+	}
+
+	f, err := os.CreateTemp("", "lazyjira-*.json")
+	if err != nil {
+		return func() tea.Msg { return jsonEditedMsg{err: err} }
+	}
+	_, err = f.WriteString(text)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return func() tea.Msg { return jsonEditedMsg{err: err} }
+	}
+
+	args := append(editorCommand(), f.Name())
+	cmd := exec.Command(args[0], args[1:]...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(f.Name())
+		if err != nil {
+			return jsonEditedMsg{text: text, err: fmt.Errorf("editor: %w", err)}
+		}
+		b, err := os.ReadFile(f.Name())
+		if err != nil {
+			return jsonEditedMsg{text: text, err: err}
+		}
+		return jsonEditedMsg{text: string(b)}
+	})
+}
+
+// parsePayload reads an edited create payload back into an issue.
+func parsePayload(text string) (*jira.Issue, error) {
+	var issue jira.Issue
+	if err := json.Unmarshal([]byte(text), &issue); err != nil {
+		return nil, err
+	}
+	if issue.Fields == nil {
+		return nil, errors.New("no fields")
+	}
+	if strings.TrimSpace(issue.Fields.Summary) == "" {
+		return nil, errors.New("summary is empty")
+	}
+	return &issue, nil
+}
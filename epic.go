@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// epicColorFieldType is the schema type of the epic color field of
+// company-managed projects.
+const epicColorFieldType = "com.pyxis.greenhopper.jira:gh-epic-color"
+
+// epicColors are the colors JIRA offers for epics, used when create metadata
+// does not list them.
+func epicColors() []allowedValue {
+	colors := make([]allowedValue, 14)
+	for i := range colors {
+		id := fmt.Sprintf("ghx-label-%d", i+1)
+		colors[i] = allowedValue{id: id, label: fmt.Sprintf("Color %d (%s)", i+1, id)}
+	}
+	return colors
+}
+
+// useEpicColor turns the epic color field, shown for epics only, into a
+// select of colors starting on create_issue.epic_color. The field takes the
+// color as a plain string.
+func useEpicColor(fields []*formField, color string) {
+	for _, f := range fields {
+		if f.schema.Custom != epicColorFieldType {
+			continue
+		}
+		if len(f.allowed) == 0 {
+			f.allowed = epicColors()
+		}
+		f.encode = func(s string) interface{} { return s }
+		for _, v := range f.allowed {
+			if color != "" && (strings.EqualFold(v.id, color) || strings.EqualFold(v.label, color)) {
+				f.value = v.id
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// fieldArg is a -field flag: a field, given by id or by name, and the value
+// to set it to.
+type fieldArg struct {
+	field string
+	value string
+}
+
+func parseFieldArgs(list []string) ([]fieldArg, error) {
+	args := make([]fieldArg, len(list))
+	for i, s := range list {
+		field, value, ok := strings.Cut(s, "=")
+		field = strings.TrimSpace(field)
+		if !ok || field == "" {
+			return nil, fmt.Errorf("-field %q is not of the form field=value", s)
+		}
+		args[i] = fieldArg{field: field, value: value}
+	}
+	return args, nil
+}
+
+// resolveFieldArgs turns -field flags into field values for issues of type
+// t. Fields named rather than given by id are looked up in the create
+// metadata. Values that parse as JSON are sent as such, except to text
+// fields, so that numbers, objects and lists can be set.
+func resolveFieldArgs(args []fieldArg, t *jira.MetaIssueType) (tcontainer.MarshalMap, error) {
+	values := tcontainer.NewMarshalMap()
+	if len(args) == 0 {
+		return values, nil
+	}
+	fields := metaFields(t)
+	for _, arg := range args {
+		f := fieldByID(fields, arg.field)
+		if f == nil {
+			for _, mf := range fields {
+				if strings.EqualFold(mf.name, arg.field) {
+					f = mf
+					break
+				}
+			}
+		}
+		if f == nil && isStoryPointsName(arg.field) {
+			f = storyPointsField(fields)
+		}
+		if f == nil && t.Fields != nil {
+			return nil, fmt.Errorf("-field: %s issues have no field %q, see the fields command", t.Name, arg.field)
+		}
+
+		id, text := arg.field, false
+		if f != nil {
+			id, text = f.id, f.schema.Type == "string"
+		}
+		var v interface{}
+		if text || json.Unmarshal([]byte(arg.value), &v) != nil {
+			v = arg.value
+		}
+		values[id] = v
+	}
+	return values, nil
+}
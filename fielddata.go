@@ -0,0 +1,158 @@
+package main
+
+import (
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Kinds of field options that create metadata leaves out, loaded in the
+// background while the user picks a type and fills in the summary.
+const (
+	dataTeams   = "teams"
+	dataOrgs    = "organizations"
+	dataLabels  = "labels"
+	dataUsers   = "users"
+	dataParents = "parents"
+)
+
+// fieldDataMsg carries the options of one kind of field. Failed loads come
+// back empty, the fields then take raw values.
+type fieldDataMsg struct {
+	kind   string
+	values []allowedValue
+	labels []string
+	// assignees are the assignee options coming from the project.
+	assignees []allowedValue
+	// cloudTeams are the teams of the Cloud team field, values those of
+	// Advanced Roadmaps.
+	cloudTeams []allowedValue
+}
+
+// loadFieldData starts loading the options the project's fields need,
+// marking each kind as pending until it is in.
+func (m *Model) loadFieldData(project *jira.MetaProject) []tea.Cmd {
+	m.pending = map[string]bool{}
+	if m.demo {
+		return nil
+	}
+	ctx, client, key := m.ctx, m.client, project.Key
+	load := func(kind string, fn func() fieldDataMsg) tea.Cmd {
+		m.pending[kind] = true
+		return func() tea.Msg {
+			msg := fn()
+			msg.kind = kind
+			return msg
+		}
+	}
+
+	var cmds []tea.Cmd
+	if portfolio, cloud := teamsToLoad(project); portfolio || cloud != "" {
+		cmds = append(cmds, load(dataTeams, func() fieldDataMsg {
+			var msg fieldDataMsg
+			if portfolio {
+				msg.values, _ = loadTeams(ctx, client)
+			}
+			if cloud != "" {
+				msg.cloudTeams, _ = loadCloudTeams(ctx, client, cloud)
+			}
+			return msg
+		}))
+	}
+	if desk := m.desk; desk != nil && needsOrganizations(project) {
+		cmds = append(cmds, load(dataOrgs, func() fieldDataMsg {
+			orgs, _ := loadOrganizations(ctx, client, desk)
+			return fieldDataMsg{values: orgs}
+		}))
+	}
+	if needsLabels(project, m.config.CreateIssue) {
+		cmds = append(cmds, load(dataLabels, func() fieldDataMsg {
+			labels, _ := loadLabels(ctx, client, key)
+			return fieldDataMsg{labels: labels}
+		}))
+	}
+	if needsParentLinks(project) {
+		cmds = append(cmds, load(dataParents, func() fieldDataMsg {
+			parents, _ := loadParentLinks(ctx, client)
+			return fieldDataMsg{values: parents}
+		}))
+	}
+	if needsUsers(project) {
+		cmds = append(cmds, load(dataUsers, func() fieldDataMsg {
+			users, err := loadUsers(ctx, client, key)
+			if err != nil {
+				return fieldDataMsg{}
+			}
+			values := userValues(users)
+			return fieldDataMsg{values: values, assignees: loadProjectAssignees(ctx, client, key, values)}
+		}))
+	}
+	return cmds
+}
+
+// useFieldData stores the options of one kind and hands them to the fields
+// built so far.
+func (m *Model) useFieldData(msg fieldDataMsg) {
+	delete(m.pending, msg.kind)
+	switch msg.kind {
+	case dataTeams:
+		m.teams, m.cloudTeams = msg.values, msg.cloudTeams
+	case dataOrgs:
+		m.orgs = msg.values
+	case dataLabels:
+		m.labels = msg.labels
+	case dataUsers:
+		m.users, m.assignees = msg.values, msg.assignees
+	case dataParents:
+		m.parents = msg.values
+	}
+	m.useFieldOptions()
+}
+
+// useFieldOptions fills in the options loaded so far on the form fields.
+func (m *Model) useFieldOptions() {
+	useTeams(m.fields, m.teams, m.cloudTeams)
+	useOrganizations(m.fields, m.orgs)
+	useLabels(m.fields, m.labels)
+	useUsers(m.fields, m.users)
+	useProjectAssignees(m.fields, m.assignees)
+	useParentLinks(m.fields, m.parents)
+}
+
+// fieldDataKind is the kind of background options f waits for, if any.
+func fieldDataKind(f *formField) string {
+	switch {
+	case isTeamField(f):
+		return dataTeams
+	case isOrganizationsField(f):
+		return dataOrgs
+	case f.id == "labels":
+		return dataLabels
+	case isUserField(f):
+		return dataUsers
+	case isParentLinkField(f):
+		return dataParents
+	}
+	return ""
+}
+
+// waitingFields names the fields whose options are still loading.
+func (m Model) waitingFields() []string {
+	var names []string
+	for _, f := range m.fields {
+		if kind := fieldDataKind(f); kind != "" && m.pending[kind] {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// loadingBadge is shown above the create form while options are loading.
+func (m Model) loadingBadge() string {
+	names := m.waitingFields()
+	if len(names) == 0 {
+		return ""
+	}
+	return m.styles.Highlight.Render("◌ loading " + strings.Join(names, ", "))
+}
@@ -0,0 +1,539 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/charmbracelet/huh"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// Fields that are either set outside of the form or that we have no
+// sensible control for on the create screen.
+var skippedFields = map[string]bool{
+	"project":    true,
+	"issuetype":  true,
+	"attachment": true,
+	"issuelinks": true,
+}
+
+type allowedValue struct {
+	id    string
+	label string
+	// ref is the property JIRA identifies the value by, usually id. A few
+	// fields only list values by name.
+	ref string
+}
+
+type fieldSchema struct {
+	Type   string
+	Items  string
+	System string
+	Custom string
+}
+
+// formField is a single create screen field along with the huh control used
+// to edit it and the value that control writes into.
+type formField struct {
+	id       string
+	name     string
+	required bool
+	schema   fieldSchema
+	allowed  []allowedValue
+
+	value  string
+	values []string
+
+	// allowCustom adds a free text input next to the options, for extra
+	// values that are not in the list. createMissing creates the components
+	// typed there that do not exist yet.
+	allowCustom   bool
+	custom        string
+	createMissing bool
+
+	// children are the options of a cascading select's second select, by
+	// parent option, and child is the one picked.
+	children map[string][]allowedValue
+	child    string
+
+	// reason is why the issue is flagged, for a Flagged field.
+	reason string
+
+	// start and end are the other end of a date range f is part of, like
+	// target start and target end.
+	start, end *formField
+
+	// encode, when set, builds the payload for a single value instead of
+	// the default for the schema.
+	encode func(string) interface{}
+}
+
+// newFormField reads a field descriptor from create metadata.
+func newFormField(id string, meta tcontainer.MarshalMap) *formField {
+	f := &formField{id: id, name: id}
+
+	if name, err := meta.String("name"); err == nil {
+		f.name = name
+	}
+	if required, err := meta.Bool("required"); err == nil {
+		f.required = required
+	}
+	if schema, err := meta.MarshalMap("schema"); err == nil {
+		f.schema.Type, _ = schema.String("type")
+		f.schema.Items, _ = schema.String("items")
+		f.schema.System, _ = schema.String("system")
+		f.schema.Custom, _ = schema.String("custom")
+	}
+	switch {
+	case f.schema.System == parentField:
+		f.encode = func(s string) interface{} { return parentValue(s) }
+	case f.schema.Custom == sprintFieldType:
+		// The sprint is set by its numeric id.
+		f.encode = func(s string) interface{} {
+			if id, err := strconv.Atoi(s); err == nil {
+				return id
+			}
+			return s
+		}
+	}
+	if values, err := meta.Array("allowedValues"); err == nil {
+		for _, v := range values {
+			if av, ok := toAllowedValue(v); ok {
+				f.allowed = append(f.allowed, av)
+			}
+		}
+		if f.schema.Custom == cascadingSelectType {
+			f.children = cascadeChildren(values)
+		}
+	}
+	return f
+}
+
+// toAllowedValue reads one entry of a field's allowedValues. Entries that
+// cannot be referred to are skipped.
+func toAllowedValue(v interface{}) (allowedValue, bool) {
+	m, err := tcontainer.ConvertToMarshalMap(v, nil)
+	if err != nil {
+		return allowedValue{}, false
+	}
+
+	var av allowedValue
+	switch id := m["id"].(type) {
+	case string:
+		av.id = id
+	case float64:
+		av.id = strconv.FormatFloat(id, 'f', -1, 64)
+	}
+	av.ref = "id"
+	for _, k := range []string{"name", "value", "key"} {
+		if s, ok := m[k].(string); ok && s != "" {
+			av.label = s
+			if av.id == "" {
+				av.id, av.ref = s, k
+			}
+			break
+		}
+	}
+	if av.id == "" {
+		return allowedValue{}, false
+	}
+	if av.label == "" {
+		av.label = av.id
+	}
+	return av, true
+}
+
+// buildFormFields turns the create metadata for an issue type into the set of
+// fields shown on the form. Summary and description always come first,
+// followed by required fields and then the remaining optional ones. Fields
+// already populated through config are left out.
+func buildFormFields(issueType *jira.MetaIssueType, c CreateIssueConfig) []*formField {
+	preset := c.CustomFields
+
+	summary := &formField{id: "summary", name: "Summary", required: true, schema: fieldSchema{Type: "string", System: "summary"}}
+	description := &formField{id: "description", name: "Description", schema: fieldSchema{Type: "string", System: "description"}}
+
+	var rest []*formField
+	if issueType != nil {
+		for id := range issueType.Fields {
+			// Descriptors that are null or not objects are of no use.
+			meta, err := issueType.Fields.MarshalMap(id)
+			if err != nil || meta == nil {
+				continue
+			}
+			switch {
+			case id == "summary":
+				summary = newFormField(id, meta)
+			case id == "description":
+				description = newFormField(id, meta)
+			case skippedFields[id]:
+			case preset != nil && preset[id] != nil:
+			case id == timeTrackingField:
+				rest = append(rest, timeTrackingFields(meta)...)
+			case id == "labels" && len(c.LabelOptions) > 0:
+				f := newFormField(id, meta)
+				f.allowed = nil
+				for _, l := range c.LabelOptions {
+					f.allowed = append(f.allowed, allowedValue{id: l, label: l})
+				}
+				f.allowCustom = c.CustomLabels
+				rest = append(rest, f)
+			case id == "components":
+				f := newFormField(id, meta)
+				useComponentInput(f, c.CreateComponents)
+				rest = append(rest, f)
+			default:
+				rest = append(rest, newFormField(id, meta))
+			}
+		}
+	}
+
+	sort.Slice(rest, func(i, j int) bool {
+		if rest[i].required != rest[j].required {
+			return rest[i].required
+		}
+		return rest[i].name < rest[j].name
+	})
+
+	fields := append([]*formField{summary, description}, rest...)
+	if c.DefaultSeverity != "" {
+		preselect(fields, "Severity", c.DefaultSeverity)
+	}
+	useEpicColor(fields, c.EpicColor)
+	pairTargetDates(fields)
+	return fields
+}
+
+// preselect picks the allowed value labelled value on the fields called
+// name, such as JSM's severity field.
+func preselect(fields []*formField, name, value string) {
+	for _, f := range fields {
+		if !strings.EqualFold(f.name, name) {
+			continue
+		}
+		for _, v := range f.allowed {
+			if strings.EqualFold(v.label, value) {
+				f.value = v.id
+			}
+		}
+	}
+}
+
+func (f *formField) title() string {
+	if f.required {
+		return f.name + "*:"
+	}
+	return f.name + ":"
+}
+
+// controls returns the huh fields used to edit f. That is a single control
+// picked from the field schema, followed by a free text input when custom
+// values are allowed.
+func (f *formField) controls() []huh.Field {
+	controls := []huh.Field{f.control()}
+	if f.allowCustom {
+		controls = append(controls, huh.NewInput().
+			Title("Other "+strings.ToLower(f.name)+":").
+			Placeholder("comma separated").
+			Value(&f.custom).
+			Validate(f.validateCustom))
+	}
+	return controls
+}
+
+func (f *formField) control() huh.Field {
+	switch {
+	case f.id == "description":
+		return huh.NewText().Title(f.title()).Value(&f.value)
+	case f.schema.Type == "array" && len(f.allowed) > 0:
+		options := make([]huh.Option[string], len(f.allowed))
+		for i, v := range f.allowed {
+			options[i] = huh.NewOption(v.label, v.id)
+		}
+		s := huh.NewMultiSelect[string]().
+			Title(f.title()).
+			Options(options...).
+			Value(&f.values).
+			Validate(func(v []string) error {
+				if f.allowCustom && strings.TrimSpace(f.custom) != "" {
+					return nil
+				}
+				return f.validateValues(v)
+			})
+		if len(options) > 8 {
+			s = s.Description("/ to filter").Height(10)
+		}
+		return s
+	case len(f.allowed) > 0:
+		var options []huh.Option[string]
+		if !f.required {
+			none := "None"
+			if f.id == "assignee" {
+				none = "Unassigned"
+			}
+			options = append(options, huh.NewOption(none, ""))
+		}
+		for _, v := range f.allowed {
+			options = append(options, huh.NewOption(v.label, v.id))
+		}
+		s := huh.NewSelect[string]().
+			Title(f.title()).
+			Options(options...).
+			Value(&f.value).
+			Validate(f.validate)
+		if len(options) > 8 {
+			s = s.Description("/ to filter").Height(10)
+		}
+		return s
+	case f.schema.Type == "array":
+		return huh.NewInput().
+			Title(f.title()).
+			Placeholder("comma separated").
+			Value(&f.value).
+			Validate(f.validate)
+	case f.schema.Type == "number":
+		return huh.NewInput().
+			Title(f.title()).
+			Value(&f.value).
+			Validate(f.validateNumber)
+	case f.schema.Type == timeTrackingField:
+		return huh.NewInput().
+			Title(f.title()).
+			Placeholder("like 2h 30m").
+			Value(&f.value).
+			Validate(f.validateDuration)
+	case f.schema.Type == "date":
+		return huh.NewInput().
+			Title(f.title()).
+			Placeholder("YYYY-MM-DD").
+			Value(&f.value).
+			Validate(f.validateDate)
+	case f.schema.System == parentField:
+		return huh.NewInput().
+			Title(f.title()).
+			Placeholder("key or link, like OPS-123").
+			Value(&f.value).
+			Validate(f.validateIssueKey)
+	default:
+		return huh.NewInput().
+			Title(f.title()).
+			Value(&f.value).
+			Validate(f.validate)
+	}
+}
+
+func (f *formField) validate(s string) error {
+	if f.required && strings.TrimSpace(s) == "" {
+		return fmt.Errorf("%s is required", f.name)
+	}
+	return nil
+}
+
+func (f *formField) validateValues(s []string) error {
+	if f.required && len(s) == 0 {
+		return fmt.Errorf("%s is required", f.name)
+	}
+	return nil
+}
+
+func (f *formField) validateNumber(s string) error {
+	if err := f.validate(s); err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return fmt.Errorf("%s must be a number", f.name)
+	}
+	return nil
+}
+
+// dateLayout is the format JIRA expects for date fields such as the due
+// date or a start date.
+const dateLayout = "2006-01-02"
+
+func (f *formField) validateDate(s string) error {
+	if err := f.validate(s); err != nil {
+		return err
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if _, err := time.Parse(dateLayout, s); err != nil {
+		return fmt.Errorf("%s must be a date like 2024-01-31", f.name)
+	}
+	return f.validateRange(s)
+}
+
+// payload returns the value to send for f in the create request, and false
+// when the field was left empty.
+func (f *formField) payload() (interface{}, bool) {
+	switch {
+	case f.schema.Type == "array" && len(f.allowed) > 0:
+		values := append([]string{}, f.values...)
+		var custom []string
+		if f.allowCustom {
+			custom = splitList(f.custom)
+		}
+		if len(values)+len(custom) == 0 {
+			return nil, false
+		}
+		if f.schema.Items == "string" {
+			return append(values, custom...), true
+		}
+		if f.encode != nil {
+			values = append(values, custom...)
+			items := make([]interface{}, len(values))
+			for i, id := range values {
+				items[i] = f.encode(id)
+			}
+			return items, true
+		}
+		items := make([]map[string]string, 0, len(values)+len(custom))
+		for _, id := range values {
+			items = append(items, f.ref(id))
+		}
+		for _, name := range custom {
+			items = append(items, f.customRef(name))
+		}
+		return items, true
+	}
+
+	value := strings.TrimSpace(f.value)
+	if value == "" {
+		return nil, false
+	}
+
+	switch {
+	case f.encode != nil:
+		return f.encode(value), true
+	case f.children != nil:
+		return f.cascadePayload(value), true
+	case len(f.allowed) > 0:
+		return f.ref(value), true
+	case f.id == "components":
+		// Components are only referred to by name here, JIRA rejects bare
+		// strings.
+		names := splitList(value)
+		items := make([]map[string]string, len(names))
+		for i, name := range names {
+			items[i] = f.customRef(name)
+		}
+		return items, len(items) > 0
+	case f.schema.Type == "array":
+		items := splitList(value)
+		return items, len(items) > 0
+	case f.schema.Type == "number":
+		n, err := strconv.ParseFloat(value, 64)
+		return n, err == nil
+	default:
+		return value, true
+	}
+}
+
+// ref is the payload pointing at one of the allowed values.
+func (f *formField) ref(id string) map[string]string {
+	for _, v := range f.allowed {
+		if v.id == id && v.ref != "" {
+			return map[string]string{v.ref: id}
+		}
+	}
+	return map[string]string{"id": id}
+}
+
+// splitList splits a comma separated list, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// applyFormFields writes the values entered on the form into the issue.
+func applyFormFields(fields []*formField, issue *jira.IssueFields) {
+	if issue.Unknowns == nil {
+		issue.Unknowns = tcontainer.NewMarshalMap()
+	}
+	for _, f := range fields {
+		v, ok := f.payload()
+		if !ok {
+			continue
+		}
+		switch f.id {
+		case "summary":
+			issue.Summary = sanitizeSummary(v.(string))
+		case "description":
+			issue.Description = normalizeNewlines(f.value)
+		case timeSpentField:
+			// Logged as work after the issue is created.
+		default:
+			issue.Unknowns[f.id] = v
+		}
+	}
+}
+
+// sanitizeSummary trims the summary and collapses runs of whitespace, line
+// breaks included, into single spaces.
+func sanitizeSummary(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// normalizeNewlines turns Windows and old Mac line endings into plain line
+// feeds, which JIRA renders consistently.
+func normalizeNewlines(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+}
+
+// fieldByID returns the field with the given id. Summary and description are
+// always present.
+func fieldByID(fields []*formField, id string) *formField {
+	for _, f := range fields {
+		if f.id == id {
+			return f
+		}
+	}
+	return nil
+}
+
+// describeError spells out the field errors of a failed create. JIRA keys
+// them by field id, they are shown under the field names of the form, which
+// come from create metadata and so are in the instance's language.
+func describeError(err error, fields []*formField) error {
+	var jerr *jira.Error
+	if !errors.As(err, &jerr) || len(jerr.Errors) == 0 {
+		return err
+	}
+	lines := append([]string{}, jerr.ErrorMessages...)
+	ids := make([]string, 0, len(jerr.Errors))
+	for id := range jerr.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		name := id
+		if f := fieldByID(fields, id); f != nil {
+			name = f.name
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", name, jerr.Errors[id]))
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
+
+// findProject returns the create metadata for a project.
+func findProject(meta *jira.CreateMetaInfo, key string) (*jira.MetaProject, error) {
+	p := meta.GetProjectWithKey(key)
+	if p == nil {
+		return nil, fmt.Errorf("project %q not found in create metadata", key)
+	}
+	return p, nil
+}
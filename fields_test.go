@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// fieldError is how go-jira reports a create JIRA rejected for a bad field
+// value.
+func fieldError() *jira.Error {
+	return &jira.Error{
+		HTTPError:     errors.New("request failed. Please analyze the request body for more details. Status code: 400"),
+		ErrorMessages: []string{},
+		Errors:        map[string]string{"customfield_10032": "Number value expected"},
+	}
+}
+
+func TestDescribeErrorUsesFieldNames(t *testing.T) {
+	err := fieldError()
+	fields := []*formField{newFormField("customfield_10032", tcontainer.MarshalMap{
+		"name":   "Story Points",
+		"schema": map[string]interface{}{"type": "number"},
+	})}
+	if got, want := describeError(err, fields).Error(), "Story Points: Number value expected"; got != want {
+		t.Errorf("describeError() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFormFieldsTruncatedMetadata(t *testing.T) {
+	issueType := &jira.MetaIssueType{Name: "Task", Fields: tcontainer.MarshalMap{
+		"customfield_1": nil,
+		"customfield_2": "not an object",
+		"customfield_3": map[string]interface{}{"name": "No Schema", "required": true},
+		"customfield_4": map[string]interface{}{"name": "No Values", "schema": map[string]interface{}{"type": "option"}},
+		"customfield_5": map[string]interface{}{"name": "Empty Values", "schema": map[string]interface{}{"type": "array", "items": "option"}, "allowedValues": []interface{}{}},
+		"customfield_6": map[string]interface{}{"name": "Bad Values", "schema": map[string]interface{}{"type": "option"}, "allowedValues": []interface{}{nil, "x", map[string]interface{}{}}},
+	}}
+
+	fields := buildFormFields(issueType, CreateIssueConfig{})
+
+	for _, id := range []string{"customfield_1", "customfield_2"} {
+		if fieldByID(fields, id) != nil {
+			t.Errorf("%s has no usable descriptor, want it left out", id)
+		}
+	}
+	for _, id := range []string{"customfield_3", "customfield_4", "customfield_5", "customfield_6"} {
+		f := fieldByID(fields, id)
+		if f == nil {
+			t.Errorf("%s missing from the form", id)
+			continue
+		}
+		if len(f.allowed) != 0 {
+			t.Errorf("%s allowed = %v, want none", id, f.allowed)
+		}
+		if len(f.controls()) == 0 {
+			t.Errorf("%s has no control", id)
+		}
+		f.payload()
+	}
+	if f := fieldByID(fields, "customfield_3"); f != nil && (!f.required || f.schema != (fieldSchema{})) {
+		t.Errorf("customfield_3 = %+v, want required with an empty schema", f)
+	}
+}
+
+func TestBuildFormFieldsNilIssueType(t *testing.T) {
+	fields := buildFormFields(nil, CreateIssueConfig{})
+	if len(fields) != 2 || fields[0].id != "summary" || fields[1].id != "description" {
+		t.Errorf("want only summary and description, got %d fields", len(fields))
+	}
+}
+
+func TestToAllowedValue(t *testing.T) {
+	for _, tc := range []struct {
+		in   interface{}
+		want allowedValue
+		ok   bool
+	}{
+		{nil, allowedValue{}, false},
+		{"x", allowedValue{}, false},
+		{map[string]interface{}{}, allowedValue{}, false},
+		{map[string]interface{}{"id": "10"}, allowedValue{id: "10", label: "10", ref: "id"}, true},
+		{map[string]interface{}{"id": float64(10), "name": "High"}, allowedValue{id: "10", label: "High", ref: "id"}, true},
+		{map[string]interface{}{"value": "Red"}, allowedValue{id: "Red", label: "Red", ref: "value"}, true},
+	} {
+		got, ok := toAllowedValue(tc.in)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("toAllowedValue(%v) = %+v, %v, want %+v, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestNormalizeNewlines(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"one\r\ntwo\r\n", "one\ntwo\n"},
+		{"one\rtwo", "one\ntwo"},
+		{"a\r\nb\rc\nd", "a\nb\nc\nd"},
+		{"a\r\r\nb", "a\n\nb"},
+		{"plain", "plain"},
+	} {
+		if got := normalizeNewlines(tc.in); got != tc.want {
+			t.Errorf("normalizeNewlines(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestComponentsWithoutAny(t *testing.T) {
+	f := newFormField("components", tcontainer.MarshalMap{
+		"name":   "Components",
+		"schema": map[string]interface{}{"type": "array", "items": "component", "system": "components"},
+	})
+	useComponentInput(f, true)
+	f.value = "api, web"
+
+	got, ok := f.payload()
+	want := []map[string]string{{"name": "api"}, {"name": "web"}}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("payload() = %v, %v, want %v", got, ok, want)
+	}
+	if names := missingComponents([]*formField{f}); !reflect.DeepEqual(names, []string{"api", "web"}) {
+		t.Errorf("missingComponents() = %v, want both to be created", names)
+	}
+}
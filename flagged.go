@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// checkboxesType is the schema type of checkbox fields, which the Flagged
+// field of JIRA Software is, with a single Impediment option.
+const checkboxesType = "com.atlassian.jira.plugin.system.customfieldtypes:multicheckboxes"
+
+// isFlaggedField tells the Flagged field by its single Impediment option,
+// as the field name is translated.
+func isFlaggedField(f *formField) bool {
+	return f.schema.Custom == checkboxesType && len(f.allowed) == 1 && f.allowed[0].label == "Impediment"
+}
+
+// reasonGroup returns a page asking why the issue is flagged, shown only
+// once f is ticked. It is nil for fields other than Flagged.
+func (f *formField) reasonGroup() *huh.Group {
+	if !isFlaggedField(f) {
+		return nil
+	}
+	return huh.NewGroup(
+		huh.NewText().
+			Title("Impediment reason:").
+			Description("Optional, added as the first comment").
+			Value(&f.reason),
+	).WithHideFunc(func() bool { return len(f.values) == 0 })
+}
+
+// flagReason is the impediment reason entered on the form, if the issue is
+// flagged.
+func flagReason(fields []*formField) string {
+	for _, f := range fields {
+		if isFlaggedField(f) && len(f.values) > 0 {
+			return strings.TrimSpace(f.reason)
+		}
+	}
+	return ""
+}
+
+// commentReason adds the impediment reason as a comment on a flagged issue,
+// reporting back on the success screen.
+func commentReason(client *jira.Client, key, reason string) tea.Cmd {
+	return func() tea.Msg {
+		if _, _, err := client.Issue.AddComment(key, &jira.Comment{Body: reason}); err != nil {
+			return noticeMsg(fmt.Sprintf("Could not add the impediment reason: %v", err))
+		}
+		return noticeMsg("Added the impediment reason as a comment")
+	}
+}
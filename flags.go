@@ -0,0 +1,16 @@
+package main
+
+import "strings"
+
+// stringList is a flag that can be given multiple times, collecting every
+// occurrence in order.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// followUpDoneMsg is a request made on a created issue after the create
+// coming back, with what it reported.
+type followUpDoneMsg struct {
+	msg tea.Msg
+}
+
+// followUp runs requests on the created issue, like linking it or logging
+// work, while the success screen is shown. Quitting waits for them, so that
+// none is cut off halfway.
+func (m *Model) followUp(cmds ...tea.Cmd) tea.Cmd {
+	wrapped := make([]tea.Cmd, len(cmds))
+	for i, cmd := range cmds {
+		cmd := cmd
+		wrapped[i] = func() tea.Msg { return followUpDoneMsg{msg: cmd()} }
+	}
+	m.inflight += len(cmds)
+	return tea.Batch(wrapped...)
+}
+
+// followUpDone counts a follow-up as done. Its notice is shown on the
+// success screen, or printed on exit once quitting, which happens as soon as
+// the last one is in.
+func (m Model) followUpDone(msg followUpDoneMsg) (tea.Model, tea.Cmd) {
+	m.inflight--
+	if notice, ok := msg.msg.(noticeMsg); ok {
+		if m.quitting {
+			if m.exitNotice != "" {
+				m.exitNotice += "\n"
+			}
+			m.exitNotice += string(notice)
+		} else {
+			m.addNotice(string(notice))
+		}
+	}
+	if m.quitting && m.inflight == 0 {
+		return m.quit()
+	}
+	return m, nil
+}
+
+// followUpView says what quitting is waiting for.
+func (m Model) followUpView() string {
+	if !m.quitting || m.inflight == 0 {
+		return ""
+	}
+	what := "update"
+	if m.inflight > 1 {
+		what = "updates"
+	}
+	return fmt.Sprintf("Finishing %d %s to created issues before quitting, quit again to stop now", m.inflight, what)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestQuitWaitsForFollowUps(t *testing.T) {
+	m := Model{cancel: func() {}}
+	cmd := m.followUp(func() tea.Msg { return noticeMsg("Logged 2h") })
+	if m.inflight != 1 {
+		t.Fatalf("inflight = %d, want 1", m.inflight)
+	}
+
+	model, quit := m.quit()
+	m = model.(Model)
+	if quit != nil || !m.quitting {
+		t.Fatal("quit did not wait for the follow-up")
+	}
+
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		msg = batch[0]()
+	}
+	model, quit = m.Update(msg)
+	m = model.(Model)
+	if quit == nil {
+		t.Fatal("want to quit once the follow-up is done")
+	}
+	if _, ok := quit().(tea.QuitMsg); !ok {
+		t.Error("want a tea.QuitMsg")
+	}
+	if m.exitNotice != "Logged 2h" {
+		t.Errorf("exitNotice = %q, want the follow-up's notice", m.exitNotice)
+	}
+}
+
+func TestQuitTwiceStopsWaiting(t *testing.T) {
+	m := Model{cancel: func() {}, inflight: 1}
+	model, _ := m.quit()
+	if _, quit := model.(Model).quit(); quit == nil {
+		t.Error("a second quit should not wait")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// updateHistory reports whether creating an issue adds its project to the
+// user's recent projects, as creating one in the browser does. Service
+// accounts can turn this off to keep their history to what they look at.
+func (c CreateIssueConfig) updateHistory() bool {
+	return c.UpdateHistory == nil || *c.UpdateHistory
+}
+
+// createEndpoint is the create endpoint with the updateHistory option.
+func (c CreateIssueConfig) createEndpoint() string {
+	return fmt.Sprintf("rest/api/2/issue?updateHistory=%t", c.updateHistory())
+}
+
+// historyCreator creates issues like client.Issue does, passing the
+// updateHistory option go-jira has no way to set.
+type historyCreator struct {
+	client   *jira.Client
+	endpoint string
+}
+
+func newCreator(c CreateIssueConfig, client *jira.Client) IssueCreator {
+	return historyCreator{client: client, endpoint: c.createEndpoint()}
+}
+
+func (h historyCreator) Create(issue *jira.Issue) (*jira.Issue, *jira.Response, error) {
+	req, err := h.client.NewRequest("POST", h.endpoint, issue)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := h.client.Do(req, nil)
+	if err != nil {
+		// Carries JIRA's messages and field errors, callers rarely look at
+		// the response.
+		return nil, resp, jira.NewJiraError(resp, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp, fmt.Errorf("could not read the created issue: %w", err)
+	}
+	created := new(jira.Issue)
+	if err := json.Unmarshal(data, created); err != nil {
+		return nil, resp, fmt.Errorf("could not read the created issue: %w", err)
+	}
+	return created, resp, nil
+}
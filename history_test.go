@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// jiraServer is a JIRA answering every request with status and body.
+func jiraServer(t *testing.T, status int, body string) *jira.Client {
+	return jiraHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+}
+
+// jiraHandler is a JIRA answering with h.
+func jiraHandler(t *testing.T, h http.HandlerFunc) *jira.Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	client, err := jira.NewClient(nil, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// fieldErrorBody is how JIRA rejects a create with a bad field value.
+const fieldErrorBody = `{"errorMessages":[],"errors":{"customfield_10032":"Number value expected"}}`
+
+func TestHistoryCreatorKeepsFieldErrors(t *testing.T) {
+	client := jiraServer(t, http.StatusBadRequest, fieldErrorBody)
+	_, resp, err := newCreator(CreateIssueConfig{}, client).Create(&jira.Issue{Fields: &jira.IssueFields{}})
+	if err == nil {
+		t.Fatal("want an error")
+	}
+	if resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("want the 400 response, got %v", resp)
+	}
+	var jerr *jira.Error
+	if !errors.As(err, &jerr) {
+		t.Fatalf("want a *jira.Error, got %T: %v", err, err)
+	}
+	if got := jerr.Errors["customfield_10032"]; got != "Number value expected" {
+		t.Errorf("field error = %q", got)
+	}
+}
+
+func TestHistoryCreatorUpdateHistory(t *testing.T) {
+	off := false
+	for _, tc := range []struct {
+		c    CreateIssueConfig
+		want string
+	}{
+		{CreateIssueConfig{}, "true"},
+		{CreateIssueConfig{UpdateHistory: &off}, "false"},
+	} {
+		var got string
+		client := jiraHandler(t, func(w http.ResponseWriter, r *http.Request) {
+			got = r.URL.Query().Get("updateHistory")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"10001","key":"OPS-1"}`))
+		})
+		if _, _, err := newCreator(tc.c, client).Create(&jira.Issue{Fields: &jira.IssueFields{}}); err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("updateHistory = %q, want %q", got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hookTimeout bounds a create_issue.exec hook, so a hung script does not
+// hold up the create.
+const hookTimeout = 30 * time.Second
+
+// applyExecHook runs the create_issue.exec command with the issue as JSON on
+// its stdin, and replaces the issue fields with the JSON it prints. When the
+// command fails or prints something unusable the issue is left as it was and
+// the error is returned.
+func applyExecHook(command string, issue *jira.Issue) error {
+	in, err := json.Marshal(issue)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+
+	var out jira.Issue
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return fmt.Errorf("output is not an issue: %w", err)
+	}
+	if out.Fields == nil {
+		return errors.New("output has no fields")
+	}
+	if strings.TrimSpace(out.Fields.Summary) == "" {
+		return errors.New("output has no summary")
+	}
+	issue.Fields = out.Fields
+	return nil
+}
+
+// execHook runs the create_issue.exec hook as part of a create from the TUI,
+// reporting a failed hook on the success screen.
+func execHook(command string, issue *jira.Issue) tea.Cmd {
+	return func() tea.Msg {
+		if err := applyExecHook(command, issue); err != nil {
+			return noticeMsg(fmt.Sprintf("exec hook failed, sent the issue as entered: %v", err))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+type UIConfig struct {
+	// IdleTimeout is how many minutes without a key press the TUI waits
+	// before saving a draft and exiting. Zero waits forever.
+	IdleTimeout int `yaml:"idle_timeout"`
+	// Compact tightens the padding and puts the form fields closer together.
+	Compact bool `yaml:"compact"`
+}
+
+// idleMsg fires once the idle timeout has passed since key press seq.
+type idleMsg int
+
+func idleTimer(minutes, seq int) tea.Cmd {
+	return tea.Tick(time.Duration(minutes)*time.Minute, func(time.Time) tea.Msg {
+		return idleMsg(seq)
+	})
+}
+
+// draft is what was entered on the create form when the session timed out.
+type draft struct {
+	Project string            `yaml:"project"`
+	Type    string            `yaml:"type"`
+	Fields  map[string]string `yaml:"fields,omitempty"`
+}
+
+func draftPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lazyjira", "draft.yaml"), nil
+}
+
+// saveDraft writes the values entered on the create form to the draft file
+// and returns its path. Nothing is written while no field is filled in.
+func saveDraft(project, issueType string, fields []*formField) (string, error) {
+	d := draft{Project: project, Type: issueType, Fields: map[string]string{}}
+	for _, f := range fields {
+		value := f.value
+		if len(f.values) > 0 {
+			value = strings.Join(f.values, ", ")
+		}
+		if strings.TrimSpace(value) != "" {
+			d.Fields[f.id] = value
+		}
+	}
+	if len(d.Fields) == 0 {
+		return "", nil
+	}
+
+	path, err := draftPath()
+	if err != nil {
+		return "", err
+	}
+	b, err := yaml.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, b, 0o600)
+}
+
+// timeOut ends an idle session, keeping whatever was typed on the create form.
+func (m Model) timeOut() (tea.Model, tea.Cmd) {
+	m.exitNotice = fmt.Sprintf("No input for %d minutes, exiting", m.config.UI.IdleTimeout)
+	if m.state == statusNormal {
+		path, err := saveDraft(m.issue.Fields.Project.Key, m.issue.Fields.Type.Name, m.fields)
+		switch {
+		case err != nil:
+			m.exitNotice += fmt.Sprintf(", could not save draft: %v", err)
+		case path != "":
+			m.exitNotice += ", draft saved to " + path
+		}
+	}
+	return m.quit()
+}
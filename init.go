@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"gopkg.in/yaml.v3"
+)
+
+// runInit asks for the JIRA site and credentials and writes them to the
+// config file. With tokenOnly set (the login command) only a new API token is
+// asked for. The credentials are checked against JIRA before anything is
+// written.
+func runInit(path string, tokenOnly bool) error {
+	var c Config
+	if _, err := os.Stat(path); err == nil {
+		if c, err = loadConfig(path); err != nil {
+			return err
+		}
+	} else if tokenOnly {
+		return fmt.Errorf("no config at %s, run lazyjira init first", path)
+	}
+	c.ApiKey = ""
+	// A new token comes with a new expiry date.
+	hadExpiry := c.TokenExpires != ""
+	c.TokenExpires = ""
+
+	token := huh.NewInput().
+		Title("API token:").
+		Password(true).
+		Value(&c.ApiKey).
+		Validate(required("API token"))
+	expires := huh.NewInput().
+		Title("Token expires on:").
+		Placeholder("YYYY-MM-DD, optional").
+		Value(&c.TokenExpires).
+		Validate(validateTokenExpires)
+
+	var group *huh.Group
+	if tokenOnly {
+		group = huh.NewGroup(token, expires)
+	} else {
+		group = huh.NewGroup(
+			huh.NewInput().Title("JIRA url:").Value(&c.JiraUrl).Validate(validateJiraUrl),
+			huh.NewInput().Title("Username:").Value(&c.Username).Validate(required("Username")),
+			token,
+			expires,
+			huh.NewInput().Title("Default project key:").Value(&c.CreateIssue.Project),
+		)
+	}
+	if err := huh.NewForm(group).Run(); err != nil {
+		return err
+	}
+
+	client, err := newClient(c)
+	if err != nil {
+		return err
+	}
+	self, _, err := client.User.GetSelf()
+	if err != nil {
+		return fmt.Errorf("could not log in to %s: %w", c.JiraUrl, err)
+	}
+
+	values := map[string]string{"api_key": c.ApiKey}
+	if c.TokenExpires != "" || hadExpiry {
+		values["token_expires"] = c.TokenExpires
+	}
+	if !tokenOnly {
+		values["jira_url"] = c.JiraUrl
+		values["username"] = c.Username
+		if c.CreateIssue.Project != "" {
+			values["create_issue.project"] = c.CreateIssue.Project
+		}
+	}
+	if err := writeConfigValues(path, values); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged in as %s, config written to %s\n", self.DisplayName, path)
+	return nil
+}
+
+func required(name string) func(string) error {
+	return func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("%s is required", name)
+		}
+		return nil
+	}
+}
+
+func validateJiraUrl(s string) error {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("JIRA url must look like https://example.atlassian.net")
+	}
+	return nil
+}
+
+// writeConfigValues sets scalar values in the config file at path, creating
+// it when missing. Keys use a dot to reach into nested sections. Everything
+// else in the file, comments included, is left as it was.
+func writeConfigValues(path string, values map[string]string) error {
+	if isTOML(path) {
+		return fmt.Errorf("%s is TOML, which lazyjira can read but not update, change it by hand", path)
+	}
+	var doc yaml.Node
+	b, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	for key, value := range values {
+		node := doc.Content[0]
+		parts := strings.Split(key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			node = mappingValue(node, part, yaml.MappingNode)
+		}
+		v := mappingValue(node, parts[len(parts)-1], yaml.ScalarNode)
+		v.Kind = yaml.ScalarNode
+		v.Tag = "!!str"
+		v.Value = value
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o600)
+}
+
+// mappingValue returns the value node for key in a mapping node, adding an
+// empty one of the given kind when the key is missing.
+func mappingValue(node *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	k := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	v := &yaml.Node{Kind: kind}
+	node.Content = append(node.Content, k, v)
+	return v
+}
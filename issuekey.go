@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// issueKeyPattern matches an issue key like OPS-123.
+var issueKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[1-9][0-9]*$`)
+
+// parseIssueKey reads an issue key as typed or pasted: the key itself, in
+// any case, or a link to the issue, like https://site/browse/OPS-123 or a
+// board link with selectedIssue=OPS-123.
+func parseIssueKey(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	key := s
+	u, err := url.Parse(s)
+	link := err == nil && u.Scheme != "" && u.Host != ""
+	if link {
+		key = u.Query().Get("selectedIssue")
+		if key == "" {
+			segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+			key = segments[len(segments)-1]
+			for i, segment := range segments[:len(segments)-1] {
+				if segment == "browse" {
+					key = segments[i+1]
+				}
+			}
+		}
+	}
+	key = strings.ToUpper(key)
+	if !issueKeyPattern.MatchString(key) {
+		if link {
+			return "", fmt.Errorf("found no issue key like OPS-123 in %s", s)
+		}
+		return "", fmt.Errorf("%q is not an issue key like OPS-123", s)
+	}
+	return key, nil
+}
+
+// validateIssueKey checks an issue key input, which may also be given a link
+// to the issue.
+func (f *formField) validateIssueKey(s string) error {
+	if err := f.validate(s); err != nil || strings.TrimSpace(s) == "" {
+		return err
+	}
+	_, err := parseIssueKey(s)
+	return err
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/charmbracelet/huh"
+)
+
+const defaultIssueType = "Bug"
+
+// issueTypes lists the issue types of a project that can be picked on the
+// create form. Sub-task types are left out unless a parent is preset, as they
+// need one, and when
+// create_issue.allowed_types is set only those types are kept. Types named in
+// create_issue.type_order come first, in that order, the rest follow as JIRA
+// lists them.
+func issueTypes(project *jira.MetaProject, c CreateIssueConfig) []*jira.MetaIssueType {
+	var types []*jira.MetaIssueType
+	for _, t := range project.IssueTypes {
+		if t == nil || (t.Subtasks && !c.hasParent()) {
+			continue
+		}
+		if len(c.AllowedTypes) > 0 && !containsFold(c.AllowedTypes, t.Name) {
+			continue
+		}
+		types = append(types, t)
+	}
+	sort.SliceStable(types, func(i, j int) bool {
+		return typeRank(c.TypeOrder, types[i].Name) < typeRank(c.TypeOrder, types[j].Name)
+	})
+	return types
+}
+
+// typeRank is the position of name in order, or len(order) when it is not
+// listed.
+func typeRank(order []string, name string) int {
+	for i, v := range order {
+		if strings.EqualFold(v, name) {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// lookupIssueType fetches the create metadata of a project and picks the
+// named issue type from it, or the default type when name is empty.
+func lookupIssueType(client *jira.Client, project, name string, c CreateIssueConfig) (*jira.MetaProject, *jira.MetaIssueType, error) {
+	metaProject, degraded, err := loadProjectMeta(context.Background(), client, project, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	if degraded {
+		fmt.Fprintln(os.Stderr, "Warning:", createMetaForbidden)
+	}
+	metaType, err := pickIssueType(metaProject, name, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	return metaProject, metaType, nil
+}
+
+// pickIssueType picks the named issue type from the create metadata of a
+// project, or the default type when name is empty.
+func pickIssueType(metaProject *jira.MetaProject, name string, c CreateIssueConfig) (*jira.MetaIssueType, error) {
+	project := metaProject.Key
+	types := issueTypes(metaProject, c)
+	switch {
+	case name != "":
+	case c.hasParent():
+		var err error
+		if name, err = defaultSubtaskType(types, project, c); err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, fmt.Errorf("project %s has several sub-task types, pick one of %s with -type or set create_issue.default_subtask_type", project, typeNames(subtaskTypes(types)))
+		}
+	default:
+		name = c.defaultType()
+	}
+	metaType, err := findIssueType(types, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkParent(metaType, project, c); err != nil {
+		return nil, err
+	}
+	return metaType, nil
+}
+
+// findIssueType returns the named issue type from types.
+func findIssueType(types []*jira.MetaIssueType, name string) (*jira.MetaIssueType, error) {
+	for _, t := range types {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("issue type %q is not available, pick one of: %s", name, typeNames(types))
+}
+
+func typeNames(types []*jira.MetaIssueType) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// subtaskTypes returns the sub-task types among types.
+func subtaskTypes(types []*jira.MetaIssueType) []*jira.MetaIssueType {
+	var subtasks []*jira.MetaIssueType
+	for _, t := range types {
+		if t.Subtasks {
+			subtasks = append(subtasks, t)
+		}
+	}
+	return subtasks
+}
+
+// defaultSubtaskType is the type of issues created under a preset parent
+// when none is given: create_issue.default_subtask_type, or the only sub-task
+// type of the project. It is empty when there are several to choose from.
+func defaultSubtaskType(types []*jira.MetaIssueType, project string, c CreateIssueConfig) (string, error) {
+	subtasks := subtaskTypes(types)
+	switch {
+	case len(subtasks) == 0:
+		return "", fmt.Errorf("project %s has no sub-task types, give the type of the child issue with -type", project)
+	case c.DefaultSubtaskType != "":
+		t, err := findIssueType(subtasks, c.DefaultSubtaskType)
+		if err != nil {
+			return "", fmt.Errorf("create_issue.default_subtask_type: %w", err)
+		}
+		return t.Name, nil
+	case len(subtasks) == 1:
+		return subtasks[0].Name, nil
+	}
+	return "", nil
+}
+
+// defaultType is the issue type used when none is given on the command
+// line, and the one preselected on the form.
+func (c CreateIssueConfig) defaultType() string {
+	if c.DefaultType != "" {
+		return c.DefaultType
+	}
+	return defaultIssueType
+}
+
+// issueTypeSelect builds the issue type picker. Long lists get a fixed height
+// so they scroll, and can be narrowed down with the select's / filter.
+func issueTypeSelect(types []*jira.MetaIssueType, value *string) *huh.Select[string] {
+	options := make([]huh.Option[string], len(types))
+	for i, t := range types {
+		options[i] = huh.NewOption(t.Name, t.Name)
+	}
+
+	s := huh.NewSelect[string]().
+		Title("Issue type:").
+		Value(value).
+		Options(options...)
+	if len(types) > 8 {
+		s = s.Description("/ to filter").Height(10)
+	}
+	return s
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyMap holds the bindings handled by the model itself. Which of them are
+// active, and shown in the help footer, depends on the current state.
+type keyMap struct {
+	// Abort quits from screens where the user is typing.
+	Abort key.Binding
+	// Quit quits from screens without text input.
+	Quit key.Binding
+	Open key.Binding
+	Copy key.Binding
+	New  key.Binding
+	Undo key.Binding
+	Link key.Binding
+	// Retry sends a create that failed along the way again.
+	Retry key.Binding
+	// Preview toggles the rendered description on the create form.
+	Preview key.Binding
+	// Edit opens the create payload as JSON in $EDITOR.
+	Edit key.Binding
+	// Screenshot attaches the clipboard image to the issue being created.
+	Screenshot key.Binding
+}
+
+var defaultKeys = keyMap{
+	Abort:      key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "quit")),
+	Quit:       key.NewBinding(key.WithKeys("q", "esc", "ctrl+c"), key.WithHelp("q", "quit")),
+	Open:       key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open")),
+	Copy:       key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy url")),
+	New:        key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new issue")),
+	Undo:       key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "delete")),
+	Link:       key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "linked issue")),
+	Retry:      key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry")),
+	Preview:    key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "preview")),
+	Edit:       key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "edit json")),
+	Screenshot: key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "attach screenshot")),
+}
+
+// formActions are the actions active while a form takes text input.
+var formActions = []string{"abort", "preview", "edit", "screenshot"}
+
+// keyGroups lists the actions that are active on the same screen, and so
+// must not share a key.
+var keyGroups = [][]string{
+	formActions,
+	{"quit", "open", "copy", "new", "undo", "link", "retry"},
+}
+
+// isPrintableKey reports whether k types a character, which form fields need
+// for themselves.
+func isPrintableKey(k string) bool {
+	r, size := utf8.DecodeRuneInString(k)
+	return size == len(k) && r != utf8.RuneError && unicode.IsPrint(r)
+}
+
+// binding returns the binding for an action name as used in the
+// keybindings config section.
+func (km *keyMap) binding(action string) *key.Binding {
+	switch action {
+	case "abort":
+		return &km.Abort
+	case "quit":
+		return &km.Quit
+	case "open":
+		return &km.Open
+	case "copy":
+		return &km.Copy
+	case "new":
+		return &km.New
+	case "undo":
+		return &km.Undo
+	case "link":
+		return &km.Link
+	case "retry":
+		return &km.Retry
+	case "preview":
+		return &km.Preview
+	case "edit":
+		return &km.Edit
+	case "screenshot":
+		return &km.Screenshot
+	}
+	return nil
+}
+
+// newKeyMap applies the keybindings config section, which maps action names
+// to the keys replacing their defaults, and checks that no two actions on the
+// same screen end up on one key and that actions active on forms are not
+// bound to keys typing a character.
+func newKeyMap(bindings map[string][]string) (keyMap, error) {
+	km := defaultKeys
+
+	actions := make([]string, 0, len(bindings))
+	for action := range bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		b := km.binding(action)
+		if b == nil {
+			return km, fmt.Errorf("keybindings: unknown action %q", action)
+		}
+		keys := bindings[action]
+		if len(keys) == 0 {
+			return km, fmt.Errorf("keybindings: %s needs at least one key", action)
+		}
+		if slices.Contains(formActions, action) {
+			for _, k := range keys {
+				if isPrintableKey(k) {
+					return km, fmt.Errorf("keybindings: %s is active on forms, where %q is typed into the field", action, k)
+				}
+			}
+		}
+		*b = key.NewBinding(key.WithKeys(keys...), key.WithHelp(keys[0], b.Help().Desc))
+	}
+
+	for _, group := range keyGroups {
+		bound := map[string]string{}
+		for _, action := range group {
+			for _, k := range km.binding(action).Keys() {
+				if other, ok := bound[k]; ok {
+					return km, fmt.Errorf("keybindings: %s is bound to both %s and %s", k, other, action)
+				}
+				bound[k] = action
+			}
+		}
+	}
+	return km, nil
+}
+
+// isFormState reports whether s shows a form, in which case most keys belong
+// to the form fields.
+func isFormState(s state) bool {
+	switch s {
+	case statePickProject, statePickType, statePickLink, statusNormal, stateReauth:
+		return true
+	}
+	return false
+}
+
+// keyBinds returns the bindings available in the current state, in the order
+// they are shown in the help footer.
+func (m Model) keyBinds() []key.Binding {
+	switch m.state {
+	case stateSuccess:
+		if m.undoLeft > 0 {
+			return []key.Binding{m.keys.Open, m.keys.Copy, m.keys.Undo, m.keys.New, m.keys.Link, m.keys.Quit}
+		}
+		return []key.Binding{m.keys.Open, m.keys.Copy, m.keys.New, m.keys.Link, m.keys.Quit}
+	case stateError:
+		if m.retryable {
+			return []key.Binding{m.keys.Retry, m.keys.Quit}
+		}
+		return []key.Binding{m.keys.Quit}
+	case stateLoading, stateCreating, stateDeleting:
+		return []key.Binding{m.keys.Quit}
+	case statusNormal:
+		return append(m.form.KeyBinds(), m.keys.Preview, m.keys.Edit, m.keys.Screenshot, m.keys.Abort)
+	}
+	if isFormState(m.state) {
+		return append(m.form.KeyBinds(), m.keys.Abort)
+	}
+	return nil
+}
+
+func (m Model) helpView() string {
+	return m.form.Help().ShortHelpView(m.keyBinds())
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// labelSample is how many recently updated issues of a project are looked
+// at to find the labels in use.
+const labelSample = 200
+
+// needsLabels reports whether the project's labels should be looked up: some
+// issue type has a labels field and no label_options are configured.
+func needsLabels(project *jira.MetaProject, c CreateIssueConfig) bool {
+	if len(c.LabelOptions) > 0 {
+		return false
+	}
+	for _, t := range project.IssueTypes {
+		if t == nil {
+			continue
+		}
+		if _, ok := t.Fields["labels"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// loadLabels collects the labels used on the project's recently updated
+// issues.
+func loadLabels(ctx context.Context, client *jira.Client, project string) ([]string, error) {
+	jql := fmt.Sprintf("project = %q AND labels is not EMPTY ORDER BY updated DESC", project)
+	endpoint := fmt.Sprintf("rest/api/2/search?fields=labels&maxResults=%d&jql=%s", labelSample, url.QueryEscape(jql))
+	var page struct {
+		Issues []struct {
+			Fields struct {
+				Labels []string `json:"labels"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	if _, err := doRequestWithContext(ctx, client, "GET", endpoint, nil, &page); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var labels []string
+	for _, issue := range page.Issues {
+		for _, l := range issue.Fields.Labels {
+			if !seen[l] {
+				seen[l] = true
+				labels = append(labels, l)
+			}
+		}
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// useLabels offers the labels already used in the project on the labels
+// field, next to an input for new ones. Without any the field stays free
+// text.
+func useLabels(fields []*formField, labels []string) {
+	f := fieldByID(fields, "labels")
+	if f == nil || len(f.allowed) > 0 || len(labels) == 0 {
+		return
+	}
+	for _, l := range labels {
+		f.allowed = append(f.allowed, allowedValue{id: l, label: l})
+	}
+	f.allowCustom = true
+}
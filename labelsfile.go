@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// readLabelsFile reads one label per line. Blank lines and lines starting
+// with # are skipped.
+func readLabelsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var labels []string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.ContainsAny(line, " \t") {
+			return nil, fmt.Errorf("%s:%d: labels cannot contain spaces", path, n)
+		}
+		labels = append(labels, line)
+	}
+	return labels, scanner.Err()
+}
+
+// labelList reads a labels value as given in create_issue.custom_fields or
+// a -field flag: a list, or a string of labels separated by commas or spaces.
+func labelList(v interface{}) []string {
+	switch v := v.(type) {
+	case []interface{}:
+		var labels []string
+		for _, l := range v {
+			if s, ok := l.(string); ok {
+				labels = append(labels, s)
+			}
+		}
+		return labels
+	case []string:
+		return v
+	case string:
+		var list []interface{}
+		if json.Unmarshal([]byte(v), &list) == nil {
+			return labelList(list)
+		}
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	return nil
+}
+
+// setLabels presets the labels of created issues to those from a labels
+// file, added to the labels config and any -field labels flags already set.
+// Duplicates are dropped. The -field labels flags are taken out of args, as
+// what they set is now part of the preset.
+func setLabels(c *CreateIssueConfig, args []fieldArg, file []string) []fieldArg {
+	if c.CustomFields == nil {
+		c.CustomFields = tcontainer.NewMarshalMap()
+	}
+	labels := labelList(c.CustomFields["labels"])
+	var rest []fieldArg
+	for _, arg := range args {
+		if strings.EqualFold(arg.field, "labels") {
+			labels = append(labels, labelList(arg.value)...)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	c.CustomFields["labels"] = uniqueLabels(append(labels, file...))
+	return rest
+}
+
+func uniqueLabels(labels []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, l := range labels {
+		if !seen[l] {
+			seen[l] = true
+			unique = append(unique, l)
+		}
+	}
+	return unique
+}
+
+// mergeLabels adds labels to those an issue already has, set either way
+// go-jira reads them, and sends the lot as the labels field.
+func mergeLabels(fields *jira.IssueFields, labels []string) {
+	all := append(append(append([]string{}, fields.Labels...), labelList(fields.Unknowns["labels"])...), labels...)
+	if len(all) == 0 {
+		return
+	}
+	fields.Labels = uniqueLabels(all)
+	delete(fields.Unknowns, "labels")
+}
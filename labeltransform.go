@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// The create_issue.label_transform settings.
+const (
+	labelTransformNone  = "none"
+	labelTransformLower = "lower"
+	labelTransformKebab = "kebab"
+)
+
+func validateLabelTransform(s string) error {
+	switch s {
+	case "", labelTransformNone, labelTransformLower, labelTransformKebab:
+		return nil
+	}
+	return fmt.Errorf("create_issue.label_transform must be %s, %s or %s, got %q", labelTransformNone, labelTransformLower, labelTransformKebab, s)
+}
+
+// transformLabel normalizes a label: lower cased, or in kebab-case, where
+// runs of anything but letters and digits become single dashes, so that
+// "Front End" turns into front-end.
+func transformLabel(transform, label string) string {
+	switch transform {
+	case labelTransformLower:
+		return strings.ToLower(label)
+	case labelTransformKebab:
+		words := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		return strings.Join(words, "-")
+	}
+	return label
+}
+
+// transformLabels applies create_issue.label_transform to the labels of an
+// issue about to be created. Labels that end up the same are sent once.
+func (c CreateIssueConfig) transformLabels(fields *jira.IssueFields) {
+	if c.LabelTransform == "" || c.LabelTransform == labelTransformNone {
+		return
+	}
+	labels := append(append([]string{}, fields.Labels...), labelList(fields.Unknowns["labels"])...)
+	if len(labels) == 0 {
+		return
+	}
+	seen := map[string]bool{}
+	var transformed []string
+	for _, l := range labels {
+		if l = transformLabel(c.LabelTransform, l); l != "" && !seen[l] {
+			seen[l] = true
+			transformed = append(transformed, l)
+		}
+	}
+	if fields.Unknowns == nil {
+		fields.Unknowns = tcontainer.NewMarshalMap()
+	}
+	fields.Labels = nil
+	fields.Unknowns["labels"] = transformed
+}
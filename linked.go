@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// pendingLink is how the next issue created is to be linked to an earlier
+// one, when splitting a report or filing a duplicate.
+type pendingLink struct {
+	linkType string
+	// verb reads between the new issue and key, like "duplicates".
+	verb string
+	key  string
+	// outward is set when the new issue is the one doing verb to key.
+	outward bool
+}
+
+type linkTypesLoadedMsg struct {
+	types []jira.IssueLinkType
+}
+
+func loadLinkTypes(client *jira.Client) tea.Cmd {
+	return func() tea.Msg {
+		types, _, err := client.IssueLinkType.GetList()
+		if err != nil {
+			return noticeMsg(fmt.Sprintf("Could not load link types: %v", err))
+		}
+		return linkTypesLoadedMsg{types}
+	}
+}
+
+// linkForm asks how the next issue relates to key, in either direction of
+// every link type.
+func linkForm(types []jira.IssueLinkType, key string, value *int) (*huh.Group, []pendingLink) {
+	var links []pendingLink
+	var options []huh.Option[int]
+	for _, t := range types {
+		for _, l := range []pendingLink{
+			{linkType: t.Name, verb: t.Outward, key: key, outward: true},
+			{linkType: t.Name, verb: t.Inward, key: key},
+		} {
+			options = append(options, huh.NewOption(fmt.Sprintf("New issue %s %s", l.verb, key), len(links)))
+			links = append(links, l)
+		}
+	}
+	s := huh.NewSelect[int]().
+		Title("Link the next issue:").
+		Options(options...).
+		Value(value)
+	if len(options) > 8 {
+		s = s.Description("/ to filter").Height(10)
+	}
+	return huh.NewGroup(s), links
+}
+
+// linkIssue links a newly created issue as l asks for, reporting back on the
+// success screen.
+func linkIssue(client *jira.Client, newKey string, l pendingLink) tea.Cmd {
+	return func() tea.Msg {
+		// JIRA reads a link as the inward issue doing the outward verb to
+		// the outward issue.
+		link := &jira.IssueLink{Type: jira.IssueLinkType{Name: l.linkType}}
+		if l.outward {
+			link.InwardIssue, link.OutwardIssue = &jira.Issue{Key: newKey}, &jira.Issue{Key: l.key}
+		} else {
+			link.InwardIssue, link.OutwardIssue = &jira.Issue{Key: l.key}, &jira.Issue{Key: newKey}
+		}
+		if _, err := client.Issue.AddLink(link); err != nil {
+			return noticeMsg(fmt.Sprintf("Could not link %s to %s: %v", newKey, l.key, err))
+		}
+		return noticeMsg(fmt.Sprintf("Linked: %s %s %s", newKey, l.verb, l.key))
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// parseRemoteLink parses a -remote-link value of the form url[,title]. The
+// title defaults to the url itself.
+func parseRemoteLink(v string) (*jira.RemoteLink, error) {
+	raw, title, _ := strings.Cut(v, ",")
+	raw = strings.TrimSpace(raw)
+	title = strings.TrimSpace(title)
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid remote link %q: expected an http(s) url", raw)
+	}
+	if title == "" {
+		title = raw
+	}
+	return &jira.RemoteLink{
+		Object: &jira.RemoteLinkObject{
+			URL:   raw,
+			Title: title,
+		},
+	}, nil
+}
+
+// addRemoteLinks attaches each link to the issue. It keeps going when one of
+// them fails and returns one error (or nil) per link.
+func addRemoteLinks(client *jira.Client, key string, links []*jira.RemoteLink) []error {
+	errs := make([]error, len(links))
+	for i, link := range links {
+		_, _, errs[i] = client.Issue.AddRemoteLink(key, link)
+	}
+	return errs
+}
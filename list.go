@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// listCommands print what the create screen of a project offers, for
+// scripting against -summary and batch files.
+var listCommands = []string{"types", "fields", "values"}
+
+// runList runs one of the listCommands for the configured project.
+func runList(w io.Writer, cmd string, client *jira.Client, c Config, issueType, field, output string) error {
+	if c.CreateIssue.Project == "" {
+		return errors.New("no project given, use -project or set create_issue.project")
+	}
+	switch cmd {
+	case "types":
+		project, degraded, err := loadProjectMeta(context.Background(), client, c.CreateIssue.Project, c.CreateIssue)
+		if err != nil {
+			return err
+		}
+		if degraded {
+			fmt.Fprintln(os.Stderr, "Warning:", createMetaForbidden)
+		}
+		return printTypes(w, project, output)
+	case "fields":
+		_, metaType, err := lookupIssueType(client, c.CreateIssue.Project, issueType, c.CreateIssue)
+		if err != nil {
+			return err
+		}
+		return printFields(w, metaType, output)
+	case "values":
+		if field == "" {
+			return errors.New("values needs the field to list, use -field")
+		}
+		_, metaType, err := lookupIssueType(client, c.CreateIssue.Project, issueType, c.CreateIssue)
+		if err != nil {
+			return err
+		}
+		return printValues(w, metaType, field, output)
+	}
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+// printTypes lists the issue types of a project, sub-tasks included.
+func printTypes(w io.Writer, project *jira.MetaProject, output string) error {
+	type typeInfo struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Subtask bool   `json:"subtask"`
+	}
+	var types []typeInfo
+	for _, t := range project.IssueTypes {
+		if t != nil {
+			types = append(types, typeInfo{ID: t.Id, Name: t.Name, Subtask: t.Subtasks})
+		}
+	}
+	if output == outputJSON {
+		return json.NewEncoder(w).Encode(types)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\t")
+	for _, t := range types {
+		name := t.Name
+		if t.Subtask {
+			name += " (sub-task)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t\n", t.ID, name)
+	}
+	return tw.Flush()
+}
+
+// metaFields reads the fields of an issue type's create screen, required
+// ones first.
+func metaFields(t *jira.MetaIssueType) []*formField {
+	var fields []*formField
+	for id := range t.Fields {
+		if meta, err := t.Fields.MarshalMap(id); err == nil && meta != nil {
+			fields = append(fields, newFormField(id, meta))
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].required != fields[j].required {
+			return fields[i].required
+		}
+		return fields[i].id < fields[j].id
+	})
+	return fields
+}
+
+// printFields lists the fields on the create screen of an issue type.
+func printFields(w io.Writer, t *jira.MetaIssueType, output string) error {
+	fields := metaFields(t)
+	if output == outputJSON {
+		type fieldInfo struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			Required bool   `json:"required"`
+			Type     string `json:"type"`
+			Items    string `json:"items,omitempty"`
+			Custom   string `json:"custom,omitempty"`
+		}
+		infos := make([]fieldInfo, len(fields))
+		for i, f := range fields {
+			infos[i] = fieldInfo{f.id, f.name, f.required, f.schema.Type, f.schema.Items, f.schema.Custom}
+		}
+		return json.NewEncoder(w).Encode(infos)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tTYPE\tREQUIRED\t")
+	for _, f := range fields {
+		typ := f.schema.Type
+		if f.schema.Items != "" {
+			typ += " of " + f.schema.Items
+		}
+		required := ""
+		if f.required {
+			required = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", f.id, f.name, typ, required)
+	}
+	return tw.Flush()
+}
+
+// printValues lists the allowed values of a field, given by id or name, as
+// create metadata has them.
+func printValues(w io.Writer, t *jira.MetaIssueType, field, output string) error {
+	var f *formField
+	for _, mf := range metaFields(t) {
+		if mf.id == field || strings.EqualFold(mf.name, field) {
+			f = mf
+			break
+		}
+	}
+	if f == nil {
+		return fmt.Errorf("%s issues have no field %q, see the fields command", t.Name, field)
+	}
+	if len(f.allowed) == 0 {
+		return fmt.Errorf("create metadata lists no values for %s", f.name)
+	}
+
+	if output == outputJSON {
+		type valueInfo struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		infos := make([]valueInfo, len(f.allowed))
+		for i, v := range f.allowed {
+			infos[i] = valueInfo{v.id, v.label}
+		}
+		return json.NewEncoder(w).Encode(infos)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\t")
+	for _, v := range f.allowed {
+		fmt.Fprintf(tw, "%s\t%s\t\n", v.id, v.label)
+	}
+	return tw.Flush()
+}
@@ -1,16 +1,17 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	jira "github.com/andygrunwald/go-jira"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/trivago/tgo/tcontainer"
-	"gopkg.in/yaml.v3"
 )
 
 const maxWidth = 160
@@ -27,14 +28,20 @@ type Styles struct {
 	Status,
 	StatusHeader,
 	Highlight,
+	Warning,
 	ErrorHeaderText,
 	Help lipgloss.Style
 }
 
-func NewStyles(lg *lipgloss.Renderer) *Styles {
+// NewStyles builds the TUI styles. compact drops most of the padding, for
+// short terminals.
+func NewStyles(lg *lipgloss.Renderer, compact bool) *Styles {
 	s := Styles{}
 	s.Base = lg.NewStyle().
 		Padding(1, 4, 0, 1)
+	if compact {
+		s.Base = s.Base.Padding(0, 1, 0, 0)
+	}
 	s.HeaderText = lg.NewStyle().
 		Foreground(indigo).
 		Bold(true).
@@ -44,11 +51,16 @@ func NewStyles(lg *lipgloss.Renderer) *Styles {
 		BorderForeground(indigo).
 		PaddingLeft(1).
 		MarginTop(1)
+	if compact {
+		s.Status = s.Status.MarginTop(0)
+	}
 	s.StatusHeader = lg.NewStyle().
 		Foreground(green).
 		Bold(true)
 	s.Highlight = lg.NewStyle().
 		Foreground(lipgloss.Color("212"))
+	s.Warning = lg.NewStyle().
+		Foreground(red)
 	s.ErrorHeaderText = s.HeaderText.Copy().
 		Foreground(red)
 	s.Help = lg.NewStyle().
@@ -56,182 +68,540 @@ func NewStyles(lg *lipgloss.Renderer) *Styles {
 	return &s
 }
 
-type state int
-
-const (
-	statusNormal state = iota
-	stateDone
-)
-
-var (
-	summary     string
-	description string
-)
-
-type Config struct {
-	JiraUrl     string            `yaml:"jira_url"`
-	Username    string            `yaml:"username"`
-	ApiKey      string            `yaml:"api_key"`
-	CreateIssue CreateIssueConfig `yaml:"create_issue"`
-}
-
-type CreateIssueConfig struct {
-	Project      string                `yaml:"project"`
-	CustomFields tcontainer.MarshalMap `yaml:"custom_fields"`
-}
-
-type Model struct {
-	state  state
-	lg     *lipgloss.Renderer
-	styles *Styles
-	form   *huh.Form
-	width  int
-}
-
-func NewModel() Model {
-	m := Model{width: maxWidth}
-	m.lg = lipgloss.DefaultRenderer()
-	m.styles = NewStyles(m.lg)
-	m.form = huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().Title("Summary:").Value(&summary),
-			huh.NewText().Title("Description:").Value(&description),
-		),
-	).
-		WithWidth(45).
-		WithShowHelp(false).
-		WithShowErrors(false)
-	return m
-}
-
-func (m Model) Init() tea.Cmd {
-	return m.form.Init()
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Oh no:", err)
+	os.Exit(1)
 }
 
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc", "ctrl+c", "q":
-			return m, tea.Quit
+func main() {
+	var (
+		configPath  = flag.String("config", "", "config file `path or url`, defaults to lazyjira/config.yaml in the user config directory")
+		timeout     = flag.String("timeout", "", "`duration` to wait for each JIRA request, like 30s, overrides timeout")
+		summary     = flag.String("summary", "", "issue summary; creates the issue without opening the form")
+		project     = flag.String("project", "", "project key, overrides repo_projects and create_issue.project")
+		issueType   = flag.String("type", "", "issue type name, defaults to create_issue.default_type")
+		sprint      = flag.String("sprint", "", "`name` of an active or future sprint to add the issue to, requires -summary")
+		parent      = flag.String("parent", "", "key of the parent issue, or a link to it, at any level of the hierarchy the project allows")
+		transition  = flag.String("transition", "", "transition or status `name` to move the created issue to, overrides create_issue.transition")
+		resolution  = flag.String("resolution", "", "resolution `name` for a -transition that sets one, overrides create_issue.resolution")
+		interactive = flag.Bool("interactive", true, "open the form when -summary is not given; with -interactive=false missing details are an error")
+		preview     = flag.Bool("preview", false, "open the form on made up data without connecting to JIRA, to try out the layout")
+		output      = flag.String("output", outputText, "`format` to print results in, text or json; json also reports a failed -summary create as JSON")
+		printCurl   = flag.Bool("print-curl", false, "print a curl command for the create request instead of sending it, requires -summary")
+		fromCommit  = flag.String("from-commit", "", "git `revision` whose subject and body become the summary and description, like -summary")
+		due         = flag.String("due", "", "due `date`, like 2024-01-31, +3d, +2w, tomorrow or friday")
+		jql         = flag.String("jql", "", "JQL `query` picking the issues for the transition command")
+		to          = flag.String("to", "", "transition or status `name` for the transition command")
+		file        = flag.String("file", "", "JSON `file` with the issue, or its fields, for the create command")
+		labelsFile  = flag.String("labels-file", "", "`file` with one label per line to add to the created issue, along with configured and -field labels")
+		compact     = flag.Bool("compact", false, "tighter form layout for short terminals, like ui.compact")
+		quiet       = flag.Bool("quiet", false, "only print the key of the created issue, requires -summary or the batch command")
+		yes         bool
+		description stringList
+		remoteLinks stringList
+		fieldFlags  stringList
+		attachPaths stringList
+	)
+	flag.Var(&description, "description", "issue description, used together with -summary; when repeated each one becomes a paragraph, joined by blank lines")
+	flag.Var(&fieldFlags, "field", "`field=value` to set on the created issue, by field id or name, can be repeated; values that parse as JSON are sent as JSON. For the values command, the field to list")
+	flag.Var(&attachPaths, "attach", "`file` to attach to the created issue, can be repeated")
+	flag.BoolVar(&yes, "yes", false, "go ahead with bulk operations without asking, implied by -interactive=false")
+	flag.BoolVar(&yes, "y", false, "short for -yes")
+	flag.Var(&remoteLinks, "remote-link", "`url[,title]` to attach to the created issue as a web link, can be repeated")
+
+	var batchFile, listCmd, editKey, createFile string
+	bulkTransition := false
+	if len(os.Args) > 1 {
+		switch cmd := os.Args[1]; cmd {
+		case "init", "login":
+			flag.CommandLine.Parse(os.Args[2:])
+			if !*interactive {
+				fail(fmt.Errorf("%s asks for the connection details and cannot run with -interactive=false", cmd))
+			}
+			path, err := defaultConfigPath()
+			if err != nil {
+				fail(err)
+			}
+			if err := runInit(path, cmd == "login"); err != nil {
+				fail(err)
+			}
+			return
+		case "config":
+			if len(os.Args) < 3 || os.Args[2] != "edit" {
+				fail(errors.New("usage: lazyjira config edit"))
+			}
+			flag.CommandLine.Parse(os.Args[3:])
+			path := expandHome(*configPath)
+			if path == "" {
+				var err error
+				if path, err = defaultConfigPath(); err != nil {
+					fail(err)
+				}
+			}
+			if err := runConfigEdit(path); err != nil {
+				fail(err)
+			}
+			return
+		case "completion":
+			if len(os.Args) != 3 {
+				fail(errors.New("usage: lazyjira completion bash|zsh|fish"))
+			}
+			if err := writeCompletion(os.Stdout, os.Args[2], flag.CommandLine); err != nil {
+				fail(err)
+			}
+			return
+		case "batch":
+			flag.CommandLine.Parse(os.Args[2:])
+			if flag.NArg() != 1 {
+				fail(errors.New("usage: lazyjira batch [flags] file.yaml"))
+			}
+			batchFile = flag.Arg(0)
+		case "create":
+			flag.CommandLine.Parse(os.Args[2:])
+			if *file == "" {
+				fail(errors.New("usage: lazyjira create -file issue.json"))
+			}
+			createFile = *file
+		case "edit":
+			flag.CommandLine.Parse(os.Args[2:])
+			if flag.NArg() != 1 {
+				fail(errors.New("usage: lazyjira edit [flags] ISSUE-123"))
+			}
+			if !*interactive {
+				fail(errors.New("edit opens a form and cannot run with -interactive=false"))
+			}
+			key, err := parseIssueKey(flag.Arg(0))
+			if err != nil {
+				fail(err)
+			}
+			editKey = key
+		case "transition":
+			flag.CommandLine.Parse(os.Args[2:])
+			if *jql == "" || *to == "" {
+				fail(errors.New("usage: lazyjira transition -jql query -to status"))
+			}
+			bulkTransition = true
+		case "types", "fields", "values":
+			flag.CommandLine.Parse(os.Args[2:])
+			listCmd = cmd
 		}
 	}
 
-	var cmds []tea.Cmd
-
-	// Process the form
-	form, cmd := m.form.Update(msg)
-	if f, ok := form.(*huh.Form); ok {
-		m.form = f
-		cmds = append(cmds, cmd)
+	if batchFile == "" && listCmd == "" && editKey == "" && createFile == "" && !bulkTransition {
+		flag.Parse()
 	}
 
-	if m.form.State == huh.StateCompleted {
-		cmds = append(cmds, tea.Quit)
+	if *fromCommit != "" {
+		subject, body, err := commitMessage(*fromCommit)
+		if err != nil {
+			fail(err)
+		}
+		if *summary == "" {
+			*summary = subject
+		}
+		if len(description) == 0 && body != "" {
+			description = stringList{body}
+		}
 	}
 
-	return m, tea.Batch(cmds...)
-}
+	if *quiet && *summary == "" && batchFile == "" {
+		fail(errors.New("-quiet requires -summary or the batch command"))
+	}
+	if err := validateOutput(*output); err != nil {
+		fail(err)
+	}
+	if *printCurl && *summary == "" {
+		fail(errors.New("-print-curl requires -summary"))
+	}
+	if *sprint != "" && *summary == "" {
+		fail(errors.New("-sprint requires -summary"))
+	}
 
-func (m Model) View() string {
-	s := m.styles
+	var links []*jira.RemoteLink
+	for _, v := range remoteLinks {
+		link, err := parseRemoteLink(v)
+		if err != nil {
+			fail(err)
+		}
+		links = append(links, link)
+	}
 
-	switch m.form.State {
-	default:
+	attachments, err := statAttachments(attachPaths)
+	if err != nil {
+		fail(err)
+	}
 
-		errors := m.form.Errors()
-		header := m.appBoundaryView("Create a JIRA Ticket")
-		if len(errors) > 0 {
-			header = m.appErrorBoundaryView(m.errorView())
+	var fieldArgs []fieldArg
+	if listCmd == "" {
+		var err error
+		if fieldArgs, err = parseFieldArgs(fieldFlags); err != nil {
+			fail(err)
 		}
+	}
 
-		footer := m.appBoundaryView(m.form.Help().ShortHelpView(m.form.KeyBinds()))
-		if len(errors) > 0 {
-			footer = m.appErrorBoundaryView("")
+	c, err := readConfig(*configPath)
+	if err != nil && !*preview {
+		fail(err)
+	}
+	if *compact {
+		c.UI.Compact = true
+	}
+	c.AssumeYes = yes || !*interactive
+	fieldArgs = c.useFieldAliases(fieldArgs)
+	if *preview {
+		if *project != "" {
+			c.CreateIssue.Project = *project
 		}
-
-		return s.Base.Render(header + "\n" + m.form.View() + "\n\n" + footer)
+		runPreview(c, *issueType)
+		return
 	}
-}
 
-func (m Model) errorView() string {
-	var s string
-	for _, err := range m.form.Errors() {
-		s += err.Error()
+	switch {
+	case *project != "":
+		c.CreateIssue.Project = *project
+	case len(c.RepoProjects) > 0:
+		if key := repoProject(c.RepoProjects, gitRemoteURL()); key != "" {
+			c.CreateIssue.Project = key
+		}
+	}
+	if *timeout != "" {
+		c.Timeout = *timeout
+	}
+	if *parent != "" {
+		if err := setParent(&c.CreateIssue, *parent); err != nil {
+			fail(err)
+		}
+	}
+	if *due != "" {
+		d, err := parseDue(*due, time.Now())
+		if err != nil {
+			fail(err)
+		}
+		setDue(&c.CreateIssue, d)
+	}
+	if *labelsFile != "" {
+		labels, err := readLabelsFile(*labelsFile)
+		if err != nil {
+			fail(err)
+		}
+		fieldArgs = setLabels(&c.CreateIssue, fieldArgs, labels)
+	}
+	if *transition != "" {
+		c.CreateIssue.Transition = *transition
+	}
+	if *resolution != "" {
+		c.CreateIssue.Resolution = *resolution
+	}
+	if err := c.validate(); err != nil {
+		fail(err)
 	}
-	return s
-}
-
-func (m Model) appBoundaryView(text string) string {
-	return lipgloss.PlaceHorizontal(
-		m.width,
-		lipgloss.Left,
-		m.styles.HeaderText.Render(text),
-		lipgloss.WithWhitespaceChars("/"),
-		lipgloss.WithWhitespaceForeground(indigo),
-	)
-}
 
-func (m Model) appErrorBoundaryView(text string) string {
-	return lipgloss.PlaceHorizontal(
-		m.width,
-		lipgloss.Left,
-		m.styles.ErrorHeaderText.Render(text),
-		lipgloss.WithWhitespaceChars("/"),
-		lipgloss.WithWhitespaceForeground(red),
-	)
-}
+	if !*interactive && batchFile == "" && listCmd == "" && editKey == "" && createFile == "" && !bulkTransition {
+		var missing []string
+		if *summary == "" {
+			missing = append(missing, "summary (-summary)")
+		}
+		if c.CreateIssue.Project == "" {
+			missing = append(missing, "project (-project or create_issue.project)")
+		}
+		if len(missing) > 0 {
+			fail(fmt.Errorf("not running interactively, missing %s", strings.Join(missing, ", ")))
+		}
+	}
 
-func main() {
-	dirname, err := os.UserHomeDir()
+	jiraClient, err := newClient(c)
 	if err != nil {
-		fmt.Println("Oh no:", err)
-		os.Exit(1)
+		fail(err)
 	}
 
-	f, err := os.ReadFile(filepath.Join(dirname, ".config", "lazyjira", "config.yaml"))
-	if err != nil {
-		fmt.Println("Oh no:", err)
-		os.Exit(1)
+	if editKey != "" {
+		if err := runEdit(jiraClient, editKey); err != nil {
+			fail(err)
+		}
+		return
 	}
 
-	var c Config
+	if bulkTransition {
+		failed, err := runTransitions(c, jiraClient, *jql, *to, *resolution)
+		if err != nil {
+			fail(err)
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
 
-	if err := yaml.Unmarshal(f, &c); err != nil {
-		fmt.Println("Oh no:", err)
-		os.Exit(1)
+	if listCmd != "" {
+		var field string
+		if len(fieldFlags) > 0 {
+			field = c.fieldID(fieldFlags[0])
+		}
+		if err := runList(os.Stdout, listCmd, jiraClient, c, *issueType, field, *output); err != nil {
+			fail(err)
+		}
+		return
 	}
 
-	model := NewModel()
-	_, err2 := tea.NewProgram(model).Run()
-	if err2 != nil {
-		fmt.Println("Oh no:", err2)
-		os.Exit(1)
+	if len(attachments) > 0 {
+		limit, err := attachmentLimit(jiraClient)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not check the attachment size limit: %v\n", err)
+		}
+		attachments = checkAttachments(os.Stderr, attachments, limit)
 	}
 
-	tp := jira.BasicAuthTransport{Username: c.Username, Password: c.ApiKey}
-	jiraClient, _ := jira.NewClient(tp.Client(), c.JiraUrl)
+	creator := newCreator(c.CreateIssue, jiraClient)
+
+	// The service desk default_request_type files requests into.
+	var desk *serviceDesk
+	if c.CreateIssue.DefaultRequestType != "" && c.CreateIssue.Project != "" && batchFile == "" && createFile == "" {
+		if desk, err = checkRequestType(jiraClient, c.CreateIssue); err != nil {
+			fail(err)
+		}
+	}
 
 	i := jira.Issue{
 		Fields: &jira.IssueFields{
-			Description: description,
 			Type: jira.IssueType{
-				Name: "Bug",
+				Name: *issueType,
 			},
 			Project: jira.Project{
 				Key: c.CreateIssue.Project,
 			},
-			Summary:  summary,
-			Unknowns: c.CreateIssue.CustomFields,
+			Unknowns: c.CreateIssue.CustomFields.Clone(),
 		},
 	}
 
-	issue, _, err := jiraClient.Issue.Create(&i)
-	if err != nil {
-		panic(err)
+	var created []*jira.Issue
+	// Actions already run on the success screen.
+	ranActions := map[string]bool{}
+	var batch *batchResult
+	// Missing details are asked for after the create form, not in scripts.
+	usedForm := false
+	switch {
+	case batchFile != "":
+		res, err := runBatch(c, jiraClient, creator, batchFile, *issueType, fieldArgs)
+		if err != nil {
+			fail(err)
+		}
+		batch = &res
+		created = res.created
+	case createFile != "":
+		issue, err := createFromFile(jiraClient, creator, c.CreateIssue, createFile, *issueType, fieldArgs)
+		if err != nil {
+			if *output == outputJSON {
+				writeErrorJSON(os.Stdout, err)
+				os.Exit(1)
+			}
+			fail(err)
+		}
+		created = append(created, issue)
+	case *summary != "":
+		if c.CreateIssue.Project == "" {
+			fail(errors.New("no project given, use -project or set create_issue.project"))
+		}
+
+		metaProject, metaType, err := lookupIssueType(jiraClient, c.CreateIssue.Project, i.Fields.Type.Name, c.CreateIssue)
+		if err != nil {
+			fail(err)
+		}
+		i.Fields.Type.Name = metaType.Name
+		extra, err := resolveFieldArgs(fieldArgs, metaType)
+		if err != nil {
+			fail(err)
+		}
+		for k, v := range extra {
+			i.Fields.Unknowns[k] = v
+		}
+		fields := buildFormFields(metaType, c.CreateIssue)
+		useTeams(fields, nil, nil)
+
+		fieldByID(fields, "summary").value = *summary
+		fieldByID(fields, "description").value = strings.Join(description, "\n\n")
+		if *sprint != "" {
+			f, err := sprintField(fields)
+			if err != nil {
+				fail(err)
+			}
+			s, err := findSprint(jiraClient, metaProject.Key, *sprint)
+			if err != nil {
+				fail(err)
+			}
+			f.value = strconv.Itoa(s.ID)
+		}
+		applyFormFields(fields, i.Fields)
+		c.CreateIssue.transformLabels(i.Fields)
+		if i.Fields.Summary == "" {
+			fail(errors.New("-summary must not be blank"))
+		}
+
+		if c.CreateIssue.Exec != "" {
+			if err := applyExecHook(c.CreateIssue.Exec, &i); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: exec hook failed, sending the issue as entered: %v\n", err)
+			}
+		}
+		if *printCurl {
+			if err := writeCurl(os.Stdout, c, &i); err != nil {
+				fail(err)
+			}
+			return
+		}
+		var issue *jira.Issue
+		if desk != nil {
+			issue, _, err = sendRequest(jiraClient, desk, metaType.Id, c.CreateIssue.DefaultRequestType, &i, "")
+		} else {
+			issue, _, err = creator.Create(&i)
+		}
+		if err != nil {
+			if *output == outputJSON {
+				writeErrorJSON(os.Stdout, err)
+				os.Exit(1)
+			}
+			fail(describeError(err, fields))
+		}
+		issue.Fields = i.Fields
+		created = append(created, issue)
+	default:
+		m := NewModel(c, jiraClient, creator, &i)
+		m.fieldArgs = fieldArgs
+		final, err := tea.NewProgram(m).Run()
+		if err != nil {
+			fail(err)
+		}
+		m = final.(Model)
+		if m.screenshot != "" {
+			// Grabbed, but no issue was created to attach it to.
+			os.Remove(m.screenshot)
+		}
+		if m.exitNotice != "" {
+			fmt.Fprintln(os.Stderr, m.exitNotice)
+		}
+		if m.err != nil {
+			// Already shown on the error view.
+			os.Exit(1)
+		}
+		created = m.session
+		usedForm = true
+		// The token may have been replaced during the session.
+		jiraClient = m.client
+		ranActions[actionCopy] = true
+		ranActions[actionOpen] = true
+	}
+
+	// The logged in user, looked up once for auto_watch.
+	var self *jira.User
+	if len(created) > 0 && c.CreateIssue.autoWatch() {
+		if self, _, err = jiraClient.User.GetSelf(); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not look up your user to watch issues: %v\n", err)
+		}
 	}
 
-	fmt.Printf("%s: %v\n", issue.Key, issue.Self)
+	// The projects auto_approve has approvals to look for in.
+	desks := newServiceDeskProjects(jiraClient)
+	for _, issue := range created {
+		if err := rememberProject(issue.Fields.Project.Key); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not remember project: %v\n", err)
+		}
+
+		switch {
+		case *quiet:
+			fmt.Println(issue.Key)
+		case *output == outputJSON:
+			writeIssueJSON(os.Stdout, c, issue)
+		default:
+			fmt.Println(issueLine(c, issue))
+		}
+
+		for _, action := range c.OnSuccess.Actions {
+			if ranActions[action] || ((*quiet || *output == outputJSON) && action == actionPrintJSON) {
+				continue
+			}
+			if err := runAction(action, c, issue); err != nil {
+				fmt.Fprintf(os.Stderr, "on_success %s failed: %v\n", action, err)
+			}
+		}
+
+		if c.OnSuccess.WebhookURL != "" {
+			if err := postWebhook(c.OnSuccess.WebhookURL, c, issue, issue.Fields.Summary); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: webhook failed: %v\n", err)
+			}
+		}
+
+		if self != nil {
+			if err := watchIssue(jiraClient, issue.Key, self); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not watch %s: %v\n", issue.Key, err)
+			}
+		}
+		if c.CreateIssue.AutoVote {
+			if err := voteIssue(jiraClient, issue.Key); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not vote for %s: %v\n", issue.Key, err)
+			}
+		}
+
+		if err := rankIssue(jiraClient, issue.Fields.Project.Key, issue.Key, c.CreateIssue.Rank); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not rank %s: %v\n", issue.Key, err)
+		}
+
+		for i, err := range addRemoteLinks(jiraClient, issue.Key, links) {
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "Could not link %s: %v\n", links[i].Object.URL, err)
+			case !*quiet && *output != outputJSON:
+				fmt.Printf("Linked %s\n", links[i].Object.URL)
+			}
+		}
+
+		if len(attachments) > 0 {
+			uploadAttachments(os.Stderr, jiraClient, issue.Key, attachments)
+		}
+
+		if c.CreateIssue.Transition != "" {
+			res, err := transitionIssue(jiraClient, issue.Key, c.CreateIssue.Transition, c.CreateIssue.Resolution, usedForm)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not transition %s: %v\n", issue.Key, err)
+			}
+			c.CreateIssue.Resolution = res
+		}
+
+		if c.CreateIssue.AutoApprove && desks.has(issue.Fields.Project.Key) {
+			approved, err := approveIssue(jiraClient, issue.Key)
+			for _, name := range approved {
+				if !*quiet && *output != outputJSON {
+					fmt.Printf("Approved %s on %s\n", name, issue.Key)
+				}
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not approve %s: %v\n", issue.Key, err)
+			}
+		}
+	}
+
+	if len(created) > 1 && !*quiet && *output != outputJSON {
+		writeSessionSummary(os.Stdout, c, created)
+	}
+
+	if batch != nil {
+		fmt.Fprintf(os.Stderr, "Created %d, skipped %d, failed %d\n", len(batch.created), batch.skipped, batch.failed)
+		if batch.failed > 0 {
+			os.Exit(1)
+		}
+	}
+}
+
+// runPreview shows the create form on demo data. Nothing is sent to JIRA.
+func runPreview(c Config, issueType string) {
+	issue := &jira.Issue{Fields: &jira.IssueFields{
+		Type:    jira.IssueType{Name: issueType},
+		Project: jira.Project{Key: c.CreateIssue.Project},
+	}}
+	m := NewModel(c, nil, nil, issue)
+	m.demo = true
+	final, err := tea.NewProgram(m).Run()
+	if err != nil {
+		fail(err)
+	}
+	if notice := final.(Model).exitNotice; notice != "" {
+		fmt.Println(notice)
+	}
 }
@@ -0,0 +1,916 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/trivago/tgo/tcontainer"
+	"golang.org/x/sync/errgroup"
+)
+
+type state int
+
+const (
+	stateLoading state = iota
+	statePickProject
+	statePickType
+	statePickLink
+	statusNormal
+	stateCreating
+	stateDeleting
+	stateReauth
+	stateError
+	stateSuccess
+	stateDone
+)
+
+var errNoProjects = errors.New("no projects available, check your permissions or the jira_url in your config")
+
+type Model struct {
+	state  state
+	lg     *lipgloss.Renderer
+	styles *Styles
+	form   *huh.Form
+	keys   keyMap
+	width  int
+	fields []*formField
+
+	config     Config
+	client     *jira.Client
+	creator    IssueCreator
+	project    *string
+	types      []*jira.MetaIssueType
+	issueType  *string
+	metaType   *jira.MetaIssueType
+	desk       *serviceDesk
+	teams      []allowedValue
+	cloudTeams []allowedValue
+	orgs       []allowedValue
+	labels     []string
+	users      []allowedValue
+	assignees  []allowedValue
+	parents    []allowedValue
+	// pending lists the kinds of field options still loading. While any
+	// field waits on them the form is partial, with the first page only, and
+	// awaiting is set when that page is done before they arrive.
+	pending  map[string]bool
+	partial  bool
+	awaiting bool
+	// linkChoice is picked on the link form out of links, and link is how
+	// the next issue is linked once created.
+	linkChoice *int
+	links      []pendingLink
+	link       *pendingLink
+	// fieldArgs are the -field flags, resolved into extra once the issue
+	// type is known. Those fields are kept off the form.
+	fieldArgs []fieldArg
+	extra     tcontainer.MarshalMap
+	// projectName is the name of the chosen project, shown next to its key.
+	projectName string
+	// onBehalfOf is the reporter email for service desk requests.
+	onBehalfOf *string
+	// participants lists who else to add to service desk requests.
+	participants *string
+	issue        *jira.Issue
+	token        *string
+	// created is the issue shown on the success screen, session every issue
+	// created since the TUI started.
+	created *jira.Issue
+	session []*jira.Issue
+	// undoLeft counts the seconds left to delete the created issue again.
+	undoLeft int
+	// deleted is the key of an issue just deleted from the success screen.
+	deleted string
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	spinner spinner.Model
+	loading string
+
+	// preview shows the rendered description beside the create form.
+	preview       bool
+	markdownStyle string
+
+	notices []string
+	// warning is shown above the create form.
+	warning string
+	// editedJSON keeps a payload edit that could not be used, so the next
+	// edit picks up from it, and jsonErr says what was wrong with it.
+	editedJSON string
+	jsonErr    string
+	err        error
+	// retryable is set when err is a create that failed along the way, and
+	// may be sent again as it is.
+	retryable bool
+
+	// screenshot is a clipboard image saved to attach to the issue once it
+	// is created, screenshotNote how grabbing it went.
+	screenshot     string
+	screenshotNote string
+
+	// self is the logged in user, shown in the status bar once known.
+	self *jira.User
+
+	// demo runs on made up data without talking to JIRA, see -preview.
+	demo bool
+
+	// keySeq counts key presses, telling stale idle timers apart.
+	keySeq int
+	// exitNotice is printed once the TUI has exited.
+	exitNotice string
+	// inflight counts the follow-ups on created issues still running, see
+	// followUp, and quitting is set once quit waits for them.
+	inflight int
+	quitting bool
+}
+
+// NewModel prepares the create form. When issue has no project yet a project
+// picker is shown first, followed by the issue type picker unless issue
+// already has a type set or only one type is available. Issues are created
+// through creator, normally newCreator on client.
+func NewModel(c Config, client *jira.Client, creator IssueCreator, issue *jira.Issue) Model {
+	m := Model{
+		width:        maxWidth,
+		config:       c,
+		client:       client,
+		creator:      creator,
+		project:      new(string),
+		issueType:    new(string),
+		onBehalfOf:   new(string),
+		participants: new(string),
+		issue:        issue,
+		token:        new(string),
+	}
+	// Already validated when the config was loaded.
+	m.keys, _ = newKeyMap(c.Keybindings)
+	m.lg = lipgloss.DefaultRenderer()
+	m.styles = NewStyles(m.lg, c.UI.Compact)
+	m.markdownStyle = markdownStyle(m.lg)
+	m.form = m.newForm(huh.NewGroup(huh.NewNote()))
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.spinner = spinner.New(spinner.WithSpinner(spinner.MiniDot), spinner.WithStyle(m.styles.Highlight))
+	m.loading = "Loading projects"
+	if issue.Fields.Project.Key != "" {
+		m.loading = "Loading fields for " + issue.Fields.Project.Key
+	}
+	return m
+}
+
+func (m Model) newForm(groups ...*huh.Group) *huh.Form {
+	form := huh.NewForm(groups...).
+		WithWidth(45).
+		WithShowHelp(false).
+		WithShowErrors(false)
+	if m.config.UI.Compact {
+		// Fields go on consecutive lines rather than a blank line apart.
+		theme := huh.ThemeCharm()
+		theme.FieldSeparator = lipgloss.NewStyle().SetString("\n")
+		form = form.WithTheme(theme)
+	}
+	return form
+}
+
+type projectsLoadedMsg struct {
+	projects jira.ProjectList
+}
+
+type createMetaLoadedMsg struct {
+	project *jira.MetaProject
+	desk    *serviceDesk
+	// degraded is set when JIRA refused the create metadata.
+	degraded bool
+}
+
+type loadFailedMsg struct {
+	err error
+}
+
+func loadProjects(ctx context.Context, client *jira.Client) tea.Cmd {
+	return func() tea.Msg {
+		projects, _, err := client.Project.GetListWithContext(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return loadFailedMsg{err}
+		}
+		return projectsLoadedMsg{*projects}
+	}
+}
+
+// loadCreateMeta fetches the create metadata of a project and checks whether
+// it is a service desk, both at once. The options of fields that create
+// metadata leaves out follow with loadFieldData.
+func loadCreateMeta(ctx context.Context, client *jira.Client, key string, c CreateIssueConfig) tea.Cmd {
+	return func() tea.Msg {
+		var msg createMetaLoadedMsg
+		g, gctx := errgroup.WithContext(ctx)
+		g.Go(func() error {
+			var err error
+			msg.project, msg.degraded, err = loadProjectMeta(gctx, client, key, c)
+			return err
+		})
+		g.Go(func() error {
+			// Not being able to tell only costs the service desk extras.
+			msg.desk, _ = findServiceDesk(gctx, client, key)
+			return nil
+		})
+		if err := g.Wait(); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return loadFailedMsg{err}
+		}
+		if msg.project.Name == "" {
+			// Create metadata was refused, the name is only for display.
+			if p, _, err := client.Project.GetWithContext(ctx, key); err == nil {
+				msg.project.Name = p.Name
+			}
+		}
+		return msg
+	}
+}
+
+// useProjects shows the project picker.
+func (m *Model) useProjects(projects jira.ProjectList) tea.Cmd {
+	if len(projects) == 0 {
+		return m.showError(errNoProjects)
+	}
+
+	// Recently used projects come first, the full list follows.
+	recent := map[string]int{}
+	for i, key := range recentProjects() {
+		recent[key] = i
+	}
+	ordered := make(jira.ProjectList, len(projects))
+	copy(ordered, projects)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ri, iok := recent[ordered[i].Key]
+		rj, jok := recent[ordered[j].Key]
+		if iok != jok {
+			return iok
+		}
+		return iok && ri < rj
+	})
+
+	options := make([]huh.Option[string], len(ordered))
+	for i, p := range ordered {
+		label := fmt.Sprintf("%s (%s)", p.Name, p.Key)
+		if _, ok := recent[p.Key]; ok {
+			label += " · recent"
+		}
+		options[i] = huh.NewOption(label, p.Key)
+	}
+	s := huh.NewSelect[string]().
+		Title("Project:").
+		Value(m.project).
+		Options(options...)
+	if len(projects) > 8 {
+		s = s.Description("/ to filter").Height(10)
+	}
+
+	m.form = m.newForm(huh.NewGroup(s))
+	m.state = statePickProject
+	return m.form.Init()
+}
+
+// useProject picks the issue type for the project, asking for one when there
+// is a choice to make.
+func (m *Model) useProject(msg createMetaLoadedMsg) tea.Cmd {
+	project := msg.project
+	m.issue.Fields.Project.Key = project.Key
+	m.projectName = project.Name
+	m.desk = msg.desk
+	m.teams, m.cloudTeams, m.orgs, m.labels = nil, nil, nil, nil
+	m.users, m.assignees, m.parents = nil, nil, nil
+	m.warning = ""
+	if msg.degraded {
+		m.warning = createMetaForbidden
+	}
+	m.types = issueTypes(project, m.config.CreateIssue)
+	if len(m.types) == 0 {
+		return m.showError(fmt.Errorf("no issue types available in project %s, check create_issue.allowed_types", project.Key))
+	}
+	cmds := m.loadFieldData(project)
+	return tea.Batch(append(cmds, m.chooseType(project))...)
+}
+
+// chooseType goes on with the issue type given or configured, or asks for one.
+func (m *Model) chooseType(project *jira.MetaProject) tea.Cmd {
+	switch {
+	case m.issue.Fields.Type.Name != "":
+		*m.issueType = m.issue.Fields.Type.Name
+	case m.config.CreateIssue.hasParent():
+		name, err := defaultSubtaskType(m.types, project.Key, m.config.CreateIssue)
+		if err != nil {
+			return m.showError(err)
+		}
+		if name == "" {
+			return m.pickType(subtaskTypes(m.types), "")
+		}
+		*m.issueType = name
+	case len(m.types) == 1:
+		*m.issueType = m.types[0].Name
+	default:
+		return m.pickType(m.types, m.config.CreateIssue.defaultType())
+	}
+	return m.useIssueType()
+}
+
+// pickType asks which of types to create, starting on preselected.
+func (m *Model) pickType(types []*jira.MetaIssueType, preselected string) tea.Cmd {
+	*m.issueType = preselected
+	m.form = m.newForm(huh.NewGroup(issueTypeSelect(types, m.issueType)))
+	m.state = statePickType
+	return m.form.Init()
+}
+
+// useIssueType builds the create form for the chosen issue type.
+func (m *Model) useIssueType() tea.Cmd {
+	t, err := findIssueType(m.types, *m.issueType)
+	if err != nil {
+		return m.showError(err)
+	}
+	if err := checkParent(t, m.issue.Fields.Project.Key, m.config.CreateIssue); err != nil {
+		return m.showError(err)
+	}
+	extra, err := resolveFieldArgs(m.fieldArgs, t)
+	if err != nil {
+		return m.showError(err)
+	}
+	m.extra = extra
+	m.metaType = t
+	m.issue.Fields.Type.Name = t.Name
+	m.issue.Fields.Unknowns = m.presets()
+	c := m.config.CreateIssue
+	c.CustomFields = m.issue.Fields.Unknowns
+	m.fields = buildFormFields(t, c)
+	m.useFieldOptions()
+	return m.useFieldForm()
+}
+
+// useFieldForm shows the create form for the fields already built.
+func (m *Model) useFieldForm() tea.Cmd {
+	// Summary and description keep their own page, everything else the
+	// create screen asks for goes on the next one.
+	var base, rest []huh.Field
+	var children []*huh.Group
+	for i, f := range m.fields {
+		if i < 2 {
+			base = append(base, f.controls()...)
+		} else {
+			rest = append(rest, f.controls()...)
+			children = append(children, f.childGroups()...)
+			if g := f.reasonGroup(); g != nil {
+				children = append(children, g)
+			}
+		}
+	}
+	if m.desk != nil {
+		base = append(base, huh.NewInput().
+			Title("Raise on behalf of (email):").
+			Value(m.onBehalfOf).
+			Validate(validateEmail))
+		base = append(base, huh.NewInput().
+			Title("Request participants:").
+			Placeholder("emails or account ids, comma separated").
+			Value(m.participants).
+			Validate(validateParticipants))
+	}
+	groups := []*huh.Group{huh.NewGroup(base...)}
+	// The next page waits for options still loading, it is added once they
+	// are in and the first page is done.
+	m.partial = len(rest) > 0 && len(m.waitingFields()) > 0
+	if len(rest) > 0 && !m.partial {
+		groups = append(groups, huh.NewGroup(rest...))
+		groups = append(groups, children...)
+	}
+
+	m.form = m.newForm(groups...)
+	m.state = statusNormal
+	return m.form.Init()
+}
+
+// continueForm rebuilds a partial form with all its pages once the options
+// are in, and moves past the first page, which is already done.
+func (m *Model) continueForm() tea.Cmd {
+	m.awaiting = false
+	cmd := m.useFieldForm()
+	return tea.Batch(cmd, m.form.NextGroup())
+}
+
+// newIssue starts over on the create form for another issue of the same
+// project and type. Summary and description are cleared, the other fields
+// keep what was entered for the last issue.
+func (m *Model) newIssue() tea.Cmd {
+	fieldByID(m.fields, "summary").value = ""
+	fieldByID(m.fields, "description").value = ""
+	m.deleted = ""
+	m.resetIssue()
+	return m.useFieldForm()
+}
+
+// resetIssue drops what the create form wrote into the issue, leaving project,
+// type and the preset fields.
+func (m *Model) resetIssue() {
+	fields := m.issue.Fields
+	fields.Summary = ""
+	fields.Description = ""
+	fields.Unknowns = m.presets()
+	m.created = nil
+	m.notices = nil
+	m.undoLeft = 0
+}
+
+// presets are the fields set through config and -field flags.
+func (m *Model) presets() tcontainer.MarshalMap {
+	fields := m.config.CreateIssue.CustomFields.Clone()
+	for k, v := range m.extra {
+		fields[k] = v
+	}
+	return fields
+}
+
+// showError switches to the error view, which stays up until the user quits.
+func (m *Model) showError(err error) tea.Cmd {
+	m.err = err
+	m.state = stateError
+	m.retryable = false
+	return nil
+}
+
+// Init starts loading what the first screen needs right away. Quitting
+// cancels anything still in flight.
+func (m Model) Init() tea.Cmd {
+	load := m.loadCreateMetaCmd(m.issue.Fields.Project.Key)
+	if m.issue.Fields.Project.Key == "" {
+		load = m.loadProjectsCmd()
+	}
+	cmds := []tea.Cmd{load, m.spinner.Tick}
+	if !m.demo {
+		cmds = append(cmds, loadSelf(m.ctx, m.client))
+	}
+	if m.config.UI.IdleTimeout > 0 {
+		cmds = append(cmds, idleTimer(m.config.UI.IdleTimeout, m.keySeq))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	if m.inflight > 0 && !m.quitting {
+		m.quitting = true
+		return m, nil
+	}
+	m.cancel()
+	return m, tea.Quit
+}
+
+// Update restarts the idle timer on every key press before handling msg.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok && m.config.UI.IdleTimeout > 0 {
+		m.keySeq++
+		model, cmd := m.update(msg)
+		return model, tea.Batch(cmd, idleTimer(m.config.UI.IdleTimeout, m.keySeq))
+	}
+	return m.update(msg)
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
+	case tea.KeyMsg:
+		switch {
+		case isFormState(m.state):
+			if key.Matches(msg, m.keys.Abort) {
+				return m.quit()
+			}
+			if m.state == statusNormal && key.Matches(msg, m.keys.Preview) {
+				m.preview = !m.preview
+				return m, nil
+			}
+			if m.state == statusNormal && key.Matches(msg, m.keys.Edit) {
+				return m, m.editJSON()
+			}
+			if m.state == statusNormal && key.Matches(msg, m.keys.Screenshot) {
+				return m, grabScreenshot()
+			}
+		case m.state == stateError && m.retryable && key.Matches(msg, m.keys.Retry):
+			m.err, m.retryable = nil, false
+			m.state = stateCreating
+			return m, tea.Batch(m.sendCmd(), m.spinner.Tick)
+		case key.Matches(msg, m.keys.Quit):
+			return m.quit()
+		case m.state == stateSuccess && key.Matches(msg, m.keys.Open):
+			return m, openIssue(browseURL(m.config, m.created.Key))
+		case m.state == stateSuccess && key.Matches(msg, m.keys.Copy):
+			return m, copyIssueURL(browseURL(m.config, m.created.Key))
+		case m.state == stateSuccess && key.Matches(msg, m.keys.New):
+			return m, m.newIssue()
+		case m.state == stateSuccess && m.client != nil && key.Matches(msg, m.keys.Link):
+			return m, loadLinkTypes(m.client)
+		case m.state == stateSuccess && m.undoLeft > 0 && key.Matches(msg, m.keys.Undo):
+			m.undoLeft = 0
+			m.state = stateDeleting
+			return m, tea.Batch(deleteIssue(m.client, m.created.Key), m.spinner.Tick)
+		}
+	case spinner.TickMsg:
+		if m.state != stateLoading && m.state != stateCreating && m.state != stateDeleting {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case selfLoadedMsg:
+		m.self = msg.user
+		return m, nil
+	case idleMsg:
+		if int(msg) == m.keySeq {
+			return m.timeOut()
+		}
+		return m, nil
+	case screenshotMsg:
+		if msg.err != nil {
+			m.screenshotNote = "Screenshot not attached: " + msg.err.Error()
+			return m, nil
+		}
+		if m.screenshot != "" {
+			os.Remove(m.screenshot)
+		}
+		m.screenshot = msg.path
+		m.screenshotNote = "The clipboard image is attached once the issue is created"
+		return m, nil
+	case noticeMsg:
+		m.addNotice(string(msg))
+		return m, nil
+	case followUpDoneMsg:
+		return m.followUpDone(msg)
+	case projectsLoadedMsg:
+		return m, m.useProjects(msg.projects)
+	case createMetaLoadedMsg:
+		return m, m.useProject(msg)
+	case jsonEditedMsg:
+		if msg.err != nil {
+			m.editedJSON, m.jsonErr = msg.text, msg.err.Error()
+			return m, nil
+		}
+		issue, err := parsePayload(msg.text)
+		if err != nil {
+			m.editedJSON, m.jsonErr = msg.text, "Edited JSON not used: "+err.Error()
+			return m, nil
+		}
+		m.editedJSON, m.jsonErr = "", ""
+		m.issue.Fields = issue.Fields
+		if m.demo {
+			m.exitNotice = demoNotice
+			return m.quit()
+		}
+		m.state = stateCreating
+		return m, tea.Batch(m.createCmd(), m.spinner.Tick)
+	case linkTypesLoadedMsg:
+		if m.state != stateSuccess {
+			return m, nil
+		}
+		m.linkChoice = new(int)
+		var group *huh.Group
+		group, m.links = linkForm(msg.types, m.created.Key, m.linkChoice)
+		m.form = m.newForm(group)
+		m.state = statePickLink
+		return m, m.form.Init()
+	case fieldDataMsg:
+		m.useFieldData(msg)
+		if m.awaiting && len(m.waitingFields()) == 0 {
+			return m, m.continueForm()
+		}
+		return m, nil
+	case loadFailedMsg:
+		return m, m.showError(msg.err)
+	case issueCreatedMsg:
+		// JIRA only sends back the key, keep a copy of what was sent.
+		fields := *m.issue.Fields
+		fields.Unknowns = fields.Unknowns.Clone()
+		msg.issue.Fields = &fields
+		m.created = msg.issue
+		m.session = append(m.session, msg.issue)
+		m.deleted = ""
+		m.undoLeft = undoWindow
+		m.state = stateSuccess
+		var followUps []tea.Cmd
+		if m.link != nil {
+			followUps = append(followUps, linkIssue(m.client, m.created.Key, *m.link))
+			m.link = nil
+		}
+		if participants := splitList(*m.participants); m.desk != nil && len(participants) > 0 {
+			followUps = append(followUps, addParticipants(m.client, m.created.Key, participants))
+		}
+		if spent := timeSpent(m.fields); spent != "" {
+			followUps = append(followUps, logWork(m.client, m.created.Key, spent))
+		}
+		if reason := flagReason(m.fields); reason != "" {
+			followUps = append(followUps, commentReason(m.client, m.created.Key, reason))
+		}
+		if m.screenshot != "" {
+			followUps = append(followUps, attachScreenshot(m.client, m.created.Key, m.screenshot))
+			m.screenshot, m.screenshotNote = "", ""
+		}
+		followUp := m.followUp(followUps...)
+		return m, tea.Batch(tuiActions(m.config, m.created), undoTick(m.created.Key), followUp)
+	case undoTickMsg:
+		if m.state != stateSuccess || m.created == nil || m.created.Key != string(msg) || m.undoLeft == 0 {
+			return m, nil
+		}
+		m.undoLeft--
+		if m.undoLeft == 0 {
+			return m, nil
+		}
+		return m, undoTick(m.created.Key)
+	case issueDeletedMsg:
+		// Back to the form as it was filled in, to fix whatever was wrong.
+		for i, issue := range m.session {
+			if issue.Key == msg.key {
+				m.session = append(m.session[:i], m.session[i+1:]...)
+				break
+			}
+		}
+		m.deleted = msg.key
+		m.resetIssue()
+		return m, m.useFieldForm()
+	case deleteFailedMsg:
+		m.state = stateSuccess
+		m.notices = append(m.notices, msg.notice())
+		return m, nil
+	case issueFailedMsg:
+		if msg.unauthorized {
+			m.state = stateReauth
+			m.form = m.tokenForm()
+			return m, m.form.Init()
+		}
+		cmd := m.showError(describeError(msg.err, m.fields))
+		m.retryable = msg.transient
+		return m, cmd
+	}
+
+	if !isFormState(m.state) {
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+
+	// Process the form
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+
+	if m.form.State == huh.StateCompleted {
+		switch m.state {
+		case statePickProject:
+			m.state = stateLoading
+			m.loading = "Loading fields for " + *m.project
+			return m, tea.Batch(m.loadCreateMetaCmd(*m.project), m.spinner.Tick)
+		case statePickType:
+			return m, m.useIssueType()
+		case statePickLink:
+			link := m.links[*m.linkChoice]
+			cmd := m.newIssue()
+			m.link = &link
+			return m, cmd
+		case statusNormal:
+			if m.partial {
+				if len(m.waitingFields()) > 0 {
+					m.awaiting = true
+					return m, tea.Batch(cmds...)
+				}
+				return m, tea.Batch(append(cmds, m.continueForm())...)
+			}
+			applyFormFields(m.fields, m.issue.Fields)
+			m.config.CreateIssue.transformLabels(m.issue.Fields)
+			if m.demo {
+				m.exitNotice = demoNotice
+				return m.quit()
+			}
+		case stateReauth:
+			m.config.ApiKey = *m.token
+			m.config.TokenExpires = ""
+			client, err := newClient(m.config)
+			if err != nil {
+				return m, m.showError(err)
+			}
+			if m.creator == newCreator(m.config.CreateIssue, m.client) {
+				m.creator = newCreator(m.config.CreateIssue, client)
+			}
+			m.client = client
+			// The exec hook and new components already ran before the
+			// create was refused, only the create itself is sent again.
+			m.state = stateCreating
+			return m, tea.Batch(append(cmds, m.sendCmd(), m.spinner.Tick)...)
+		}
+		m.state = stateCreating
+		cmds = append(cmds, m.createCmd(), m.spinner.Tick)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// createCmd sends the issue off. Service desk tickets raised for someone else
+// go through the customer request API, everything else is a plain create.
+// The create_issue.exec hook, if any, gets to adjust the issue first.
+func (m Model) createCmd() tea.Cmd {
+	create := m.sendCmd()
+	var steps []tea.Cmd
+	if names := missingComponents(m.fields); len(names) > 0 {
+		steps = append(steps, createComponents(m.client, m.issue.Fields.Project.Key, names))
+	}
+	if m.config.CreateIssue.Exec != "" {
+		steps = append(steps, execHook(m.config.CreateIssue.Exec, m.issue))
+	}
+	if len(steps) == 0 {
+		return create
+	}
+	return tea.Sequence(append(steps, create)...)
+}
+
+// sendCmd sends the create request for the issue as it stands, with no
+// steps ahead of it.
+func (m Model) sendCmd() tea.Cmd {
+	if requestType := m.config.CreateIssue.DefaultRequestType; m.desk != nil && (*m.onBehalfOf != "" || requestType != "") {
+		return createRequest(m.client, m.desk, m.metaType.Id, requestType, m.issue, *m.onBehalfOf)
+	}
+	return createIssue(m.creator, m.issue)
+}
+
+// tokenForm asks for a fresh API token after JIRA rejected the current one.
+// Everything already entered on the create form is kept.
+func (m Model) tokenForm() *huh.Form {
+	*m.token = ""
+	return m.newForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("API token:").
+				Password(true).
+				Value(m.token),
+		),
+	)
+}
+
+func (m Model) View() string {
+	return m.styles.Base.Render(m.screenView() + "\n" + m.statusBarView())
+}
+
+// screenView renders the current state, without the status bar.
+func (m Model) screenView() string {
+	s := m.styles
+
+	switch m.state {
+	case stateLoading:
+		return m.appBoundaryView(m.spinner.View() + " " + m.loading)
+	case stateCreating:
+		return m.appBoundaryView(m.spinner.View() + " Creating issue...")
+	case stateDeleting:
+		return m.appBoundaryView(m.spinner.View() + " Deleting " + m.created.Key + "...")
+	case stateError:
+		header := m.appErrorBoundaryView("Something went wrong")
+		footer := m.appErrorBoundaryView(m.helpView())
+		return header + "\n\n" + m.err.Error() + "\n\n" + footer
+	case stateSuccess:
+		title := "Created " + m.created.Key
+		if n := len(m.session); n > 1 {
+			title += fmt.Sprintf(" (%d this session)", n)
+		}
+		header := m.appBoundaryView(title)
+		body := s.Highlight.Render(browseURL(m.config, m.created.Key))
+		if m.undoLeft > 0 {
+			body += "\n" + s.Help.Render(fmt.Sprintf("Press u within %ds to delete", m.undoLeft))
+		}
+		for _, notice := range m.notices {
+			body += "\n" + s.Help.Render(notice)
+		}
+		if waiting := m.followUpView(); waiting != "" {
+			body += "\n" + s.Warning.Render(waiting)
+		}
+		footer := m.appBoundaryView(m.helpView())
+		return header + "\n\n" + body + "\n\n" + footer
+	case stateReauth:
+		header := m.appErrorBoundaryView("Token rejected, enter a new API token")
+		footer := m.appBoundaryView(m.helpView())
+		return header + "\n" + m.form.View() + "\n\n" + footer
+	default:
+
+		errors := m.form.Errors()
+		title := "Create a JIRA Ticket"
+		if m.projectName != "" && m.state == statusNormal {
+			title += fmt.Sprintf(" in %s (%s)", m.projectName, m.issue.Fields.Project.Key)
+		}
+		if n := len(m.session); n > 0 {
+			title += fmt.Sprintf(" (%d created)", n)
+		}
+		if m.link != nil && m.state == statusNormal {
+			title += fmt.Sprintf(" that %s %s", m.link.verb, m.link.key)
+		}
+		if m.deleted != "" {
+			title = "Deleted " + m.deleted
+		}
+		header := m.appBoundaryView(title)
+		if len(errors) > 0 {
+			header = m.appErrorBoundaryView(m.errorView())
+		}
+
+		footer := m.appBoundaryView(m.helpView())
+		if len(errors) > 0 {
+			footer = m.appErrorBoundaryView("")
+		}
+
+		body := m.form.View()
+		if badge := m.loadingBadge(); badge != "" {
+			body = badge + "\n\n" + body
+		}
+		if m.jsonErr != "" {
+			body = s.Warning.Render(m.jsonErr) + "\n\n" + body
+		}
+		switch {
+		case m.screenshot != "":
+			body = s.Highlight.Render(m.screenshotNote) + "\n\n" + body
+		case m.screenshotNote != "":
+			body = s.Warning.Render(m.screenshotNote) + "\n\n" + body
+		}
+		if m.warning != "" {
+			body = s.Warning.Render(m.warning) + "\n\n" + body
+		}
+		if waiting := m.followUpView(); waiting != "" {
+			body = s.Warning.Render(waiting) + "\n\n" + body
+		}
+		if m.state == statusNormal && m.preview {
+			body = lipgloss.JoinHorizontal(lipgloss.Top, body, m.previewView(lipgloss.Width(body)))
+		}
+
+		return header + "\n" + body + "\n\n" + footer
+	}
+}
+
+// noticeMsg carries a short status line shown under the created issue. The
+// last few are kept.
+type noticeMsg string
+
+func (m *Model) addNotice(notice string) {
+	m.notices = append(m.notices, notice)
+	if len(m.notices) > 3 {
+		m.notices = m.notices[len(m.notices)-3:]
+	}
+}
+
+func openIssue(url string) tea.Cmd {
+	return func() tea.Msg {
+		if err := openURL(url); err != nil {
+			return noticeMsg("Could not open browser: " + err.Error())
+		}
+		return noticeMsg("Opened in browser")
+	}
+}
+
+func copyIssueURL(url string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(url); err != nil {
+			return noticeMsg("Could not copy: " + err.Error())
+		}
+		return noticeMsg("Copied to clipboard")
+	}
+}
+
+func (m Model) errorView() string {
+	var s string
+	for _, err := range m.form.Errors() {
+		s += err.Error()
+	}
+	return s
+}
+
+func (m Model) appBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.HeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(indigo),
+	)
+}
+
+func (m Model) appErrorBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.ErrorHeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(red),
+	)
+}
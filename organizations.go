@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// organizationsFieldType is the schema type of the JSM field holding the
+// customer organizations a request belongs to.
+const organizationsFieldType = "com.atlassian.servicedesk:sd-customer-organizations"
+
+func isOrganizationsField(f *formField) bool {
+	return f.schema.Custom == organizationsFieldType
+}
+
+// needsOrganizations reports whether any issue type of the project has an
+// organizations field, which create metadata never lists the values for.
+func needsOrganizations(project *jira.MetaProject) bool {
+	for _, t := range project.IssueTypes {
+		if t == nil {
+			continue
+		}
+		for id := range t.Fields {
+			meta, err := t.Fields.MarshalMap(id)
+			if err != nil || meta == nil {
+				continue
+			}
+			if f := newFormField(id, meta); isOrganizationsField(f) && len(f.allowed) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadOrganizations lists the customer organizations of a service desk.
+func loadOrganizations(ctx context.Context, client *jira.Client, desk *serviceDesk) ([]allowedValue, error) {
+	var values []allowedValue
+	for start := 0; ; {
+		var page struct {
+			Values []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"values"`
+			Size       int  `json:"size"`
+			IsLastPage bool `json:"isLastPage"`
+		}
+		endpoint := fmt.Sprintf("rest/servicedeskapi/servicedesk/%s/organization?start=%d", desk.ID, start)
+		if _, err := doRequestWithContext(ctx, client, "GET", endpoint, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, o := range page.Values {
+			values = append(values, allowedValue{id: o.ID, label: o.Name, ref: "id"})
+		}
+		if page.IsLastPage || page.Size == 0 {
+			return values, nil
+		}
+		start += page.Size
+	}
+}
+
+// useOrganizations turns organizations fields into a multi-select of the
+// service desk's organizations. The field takes the bare numeric ids.
+func useOrganizations(fields []*formField, organizations []allowedValue) {
+	for _, f := range fields {
+		if !isOrganizationsField(f) {
+			continue
+		}
+		if len(f.allowed) == 0 {
+			f.allowed = organizations
+		}
+		f.encode = func(v string) interface{} {
+			if n, err := strconv.Atoi(v); err == nil {
+				return n
+			}
+			return v
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// Formats accepted by -output.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+func validateOutput(format string) error {
+	switch format {
+	case outputText, outputJSON:
+		return nil
+	}
+	return fmt.Errorf("-output must be %s or %s, got %q", outputText, outputJSON, format)
+}
+
+// writeIssueJSON prints the identifiers of a created issue as a JSON line.
+func writeIssueJSON(w io.Writer, c Config, issue *jira.Issue) error {
+	return json.NewEncoder(w).Encode(map[string]string{
+		"id":   issue.ID,
+		"key":  issue.Key,
+		"self": issue.Self,
+		"url":  browseURL(c, issue.Key),
+	})
+}
+
+// writeErrorJSON prints err as a JSON line. The messages and field errors
+// JIRA sent back are kept apart so scripts can point at the offending field.
+func writeErrorJSON(w io.Writer, err error) error {
+	messages := []string{err.Error()}
+	fields := map[string]string{}
+	var jerr *jira.Error
+	if errors.As(err, &jerr) {
+		messages = jerr.ErrorMessages
+		if messages == nil {
+			messages = []string{}
+		}
+		if jerr.Errors != nil {
+			fields = jerr.Errors
+		}
+	}
+
+	type errorBody struct {
+		Messages []string          `json:"messages"`
+		Fields   map[string]string `json:"fields"`
+	}
+	return json.NewEncoder(w).Encode(map[string]errorBody{
+		"error": {Messages: messages, Fields: fields},
+	})
+}
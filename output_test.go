@@ -0,0 +1,29 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteErrorJSONFieldErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeErrorJSON(&buf, fieldError()); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Error struct {
+			Messages []string          `json:"messages"`
+			Fields   map[string]string `json:"fields"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, buf.String())
+	}
+	if len(got.Error.Messages) != 0 {
+		t.Errorf("messages = %q, want none", got.Error.Messages)
+	}
+	if got.Error.Fields["customfield_10032"] != "Number value expected" {
+		t.Errorf("fields = %v, want the field error", got.Error.Fields)
+	}
+}
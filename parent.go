@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// parentField is the system field linking an issue to its parent, whatever
+// level of the hierarchy the parent sits on.
+const parentField = "parent"
+
+// parentValue is the payload for the parent field, given the key or a link
+// to the parent.
+func parentValue(s string) map[string]string {
+	key, err := parseIssueKey(s)
+	if err != nil {
+		// Left for JIRA to reject.
+		key = strings.ToUpper(strings.TrimSpace(s))
+	}
+	return map[string]string{"key": key}
+}
+
+// setParent presets the parent of created issues, given its key or a link to
+// it. It goes in with the custom fields, which keeps it off the form.
+func setParent(c *CreateIssueConfig, s string) error {
+	key, err := parseIssueKey(s)
+	if err != nil {
+		return fmt.Errorf("-parent: %w", err)
+	}
+	if c.CustomFields == nil {
+		c.CustomFields = tcontainer.NewMarshalMap()
+	}
+	c.CustomFields[parentField] = parentValue(key)
+	return nil
+}
+
+// hasParent reports whether created issues get a preset parent, which makes
+// them sub-tasks unless another type is asked for.
+func (c CreateIssueConfig) hasParent() bool {
+	return c.CustomFields[parentField] != nil
+}
+
+// checkParent fails when a parent is preset but issues of type t cannot have
+// one. JIRA lists the parent field in the create metadata only for types that
+// take a parent.
+func checkParent(t *jira.MetaIssueType, project string, c CreateIssueConfig) error {
+	if !c.hasParent() || t.Fields == nil {
+		// Without metadata there is nothing to check against.
+		return nil
+	}
+	if _, ok := t.Fields[parentField]; ok {
+		return nil
+	}
+	return fmt.Errorf("%s issues in project %s cannot have a parent", t.Name, project)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// parentLinkType is the schema type of the Parent Link field of Advanced
+// Roadmaps, which places issues under levels above epics, like an epic
+// under an initiative. It is set to the parent's key.
+const parentLinkType = "com.atlassian.jpo:jpo-custom-field-parent"
+
+// parentLinkSample is how many candidate parents are offered.
+const parentLinkSample = 100
+
+func isParentLinkField(f *formField) bool {
+	return f.schema.Custom == parentLinkType
+}
+
+// needsParentLinks reports whether any issue type of the project has a
+// Parent Link field.
+func needsParentLinks(project *jira.MetaProject) bool {
+	for _, t := range project.IssueTypes {
+		if t == nil {
+			continue
+		}
+		for id := range t.Fields {
+			meta, err := t.Fields.MarshalMap(id)
+			if err != nil || meta == nil {
+				continue
+			}
+			if isParentLinkField(newFormField(id, meta)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadParentLinks lists the open issues of the levels above epics, the
+// ones an issue can be given as Parent Link, most recently updated first.
+func loadParentLinks(ctx context.Context, client *jira.Client) ([]allowedValue, error) {
+	ids, err := parentLinkTypes(ctx, client)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	jql := fmt.Sprintf("issuetype in (%s) AND statusCategory != Done ORDER BY updated DESC", strings.Join(ids, ", "))
+	endpoint := fmt.Sprintf("rest/api/2/search?fields=summary,issuetype&maxResults=%d&jql=%s", parentLinkSample, url.QueryEscape(jql))
+	var result struct {
+		Issues []jira.Issue `json:"issues"`
+	}
+	if _, err := doRequestWithContext(ctx, client, "GET", endpoint, nil, &result); err != nil {
+		return nil, err
+	}
+	values := make([]allowedValue, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		label := issue.Key
+		if issue.Fields != nil {
+			label = fmt.Sprintf("%s %s: %s", issue.Key, issue.Fields.Type.Name, issue.Fields.Summary)
+		}
+		values = append(values, allowedValue{id: issue.Key, label: label})
+	}
+	return values, nil
+}
+
+// parentLinkTypes returns the ids of the issue types of the levels above
+// epics. Cloud has the level on the issue type, Server and Data Center only
+// in the Advanced Roadmaps hierarchy, which lists the levels from sub-tasks
+// up: sub-tasks, stories and epics come first.
+func parentLinkTypes(ctx context.Context, client *jira.Client) ([]string, error) {
+	var types []struct {
+		ID             string `json:"id"`
+		HierarchyLevel int    `json:"hierarchyLevel"`
+	}
+	if _, err := doRequestWithContext(ctx, client, "GET", "rest/api/2/issuetype", nil, &types); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var ids []string
+	for _, t := range types {
+		if t.HierarchyLevel > 1 && !seen[t.ID] {
+			seen[t.ID] = true
+			ids = append(ids, t.ID)
+		}
+	}
+	if len(ids) > 0 {
+		return ids, nil
+	}
+
+	var levels []struct {
+		IssueTypes []int64 `json:"issueTypes"`
+	}
+	resp, err := doRequestWithContext(ctx, client, "GET", "rest/jpo/1.0/hierarchy", nil, &levels)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			// Advanced Roadmaps is not installed.
+			return nil, nil
+		}
+		return nil, err
+	}
+	for i := 3; i < len(levels); i++ {
+		for _, id := range levels[i].IssueTypes {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+	}
+	return ids, nil
+}
+
+// useParentLinks offers the candidate parents on Parent Link fields, which
+// take the plain key.
+func useParentLinks(fields []*formField, parents []allowedValue) {
+	for _, f := range fields {
+		if isParentLinkField(f) && len(f.allowed) == 0 && len(parents) > 0 {
+			f.allowed = parents
+			f.encode = func(s string) interface{} { return s }
+		}
+	}
+}
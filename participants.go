@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// validateParticipants checks that every entry of a comma separated list
+// looks like an email address or an account id. Whether they belong to an
+// actual user is only known once they are resolved.
+func validateParticipants(s string) error {
+	for _, p := range splitList(s) {
+		if strings.Contains(p, "@") {
+			if err := validateEmail(p); err != nil {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+			continue
+		}
+		if strings.ContainsAny(p, " \t") {
+			return fmt.Errorf("%s is neither an email address nor an account id", p)
+		}
+	}
+	return nil
+}
+
+// resolveAccountID returns the account id of the user behind an email
+// address or account id.
+func resolveAccountID(client *jira.Client, participant string) (string, error) {
+	if !strings.Contains(participant, "@") {
+		user, _, err := client.User.GetByAccountID(participant)
+		if err != nil {
+			return "", fmt.Errorf("no user with account id %s", participant)
+		}
+		return user.AccountID, nil
+	}
+
+	users, _, err := client.User.Find(url.QueryEscape(participant))
+	if err != nil {
+		return "", err
+	}
+	for _, u := range users {
+		if strings.EqualFold(u.EmailAddress, participant) {
+			return u.AccountID, nil
+		}
+	}
+	if len(users) == 1 {
+		return users[0].AccountID, nil
+	}
+	return "", fmt.Errorf("no user with email %s", participant)
+}
+
+// addParticipants adds request participants to a created service desk
+// request, reporting back on the success screen.
+func addParticipants(client *jira.Client, key string, participants []string) tea.Cmd {
+	return func() tea.Msg {
+		var ids []string
+		var errs []error
+		for _, p := range participants {
+			id, err := resolveAccountID(client, p)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			ids = append(ids, id)
+		}
+		if len(ids) > 0 {
+			endpoint := fmt.Sprintf("rest/servicedeskapi/request/%s/participant", key)
+			if _, err := doRequest(client, "POST", endpoint, map[string][]string{"accountIds": ids}, nil); err != nil {
+				errs = append(errs, err)
+				ids = nil
+			}
+		}
+		if err := errors.Join(errs...); err != nil {
+			return noticeMsg(fmt.Sprintf("Added %d participants, %v", len(ids), strings.ReplaceAll(err.Error(), "\n", ", ")))
+		}
+		return noticeMsg(fmt.Sprintf("Added %d participants", len(ids)))
+	}
+}
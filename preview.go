@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// previewMinWidth is the narrowest the description preview gets before it is
+// left out.
+const previewMinWidth = 30
+
+// previewView renders the description as markdown in a pane beside the
+// form, or returns an empty string when there is no room for it.
+func (m Model) previewView(formWidth int) string {
+	s := m.styles
+	width := m.width - formWidth - s.Status.GetHorizontalFrameSize()
+	if width < previewMinWidth {
+		return ""
+	}
+
+	description := strings.TrimSpace(fieldByID(m.fields, "description").value)
+	body := s.Help.Render("Nothing to preview yet")
+	if description != "" {
+		r, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(m.markdownStyle),
+			glamour.WithWordWrap(width-2),
+		)
+		if err == nil {
+			body, err = r.Render(description)
+		}
+		if err != nil {
+			body = err.Error()
+		}
+		body = strings.Trim(body, "\n")
+	}
+
+	return s.Status.
+		Width(width).
+		Render(s.StatusHeader.Render("Preview") + "\n" + body)
+}
+
+// markdownStyle picks the glamour style matching the terminal background.
+// It is worked out before the TUI starts, later queries would race with key
+// input.
+func markdownStyle(lg *lipgloss.Renderer) string {
+	if lg.HasDarkBackground() {
+		return "dark"
+	}
+	return "light"
+}
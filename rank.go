@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+const (
+	rankTop    = "top"
+	rankBottom = "bottom"
+)
+
+func validateRank(rank string) error {
+	switch rank {
+	case "", rankTop, rankBottom:
+		return nil
+	}
+	return fmt.Errorf("create_issue.rank must be %s or %s, got %q", rankTop, rankBottom, rank)
+}
+
+// rankIssue moves a freshly created issue to the top or bottom of the first
+// board of its project. Projects without a board are skipped.
+func rankIssue(client *jira.Client, project, key, rank string) error {
+	if rank == "" {
+		return nil
+	}
+
+	boards, _, err := client.Board.GetAllBoards(&jira.BoardListOptions{ProjectKeyOrID: project})
+	if err != nil {
+		return err
+	}
+	if len(boards.Values) == 0 {
+		return nil
+	}
+	board := boards.Values[0]
+
+	order := "ASC"
+	if rank == rankBottom {
+		order = "DESC"
+	}
+	endpoint := fmt.Sprintf("rest/agile/1.0/board/%d/issue?maxResults=2&fields=key&jql=%s", board.ID, url.QueryEscape("ORDER BY Rank "+order))
+	var page struct {
+		Issues []jira.Issue `json:"issues"`
+	}
+	if _, err := doRequest(client, "GET", endpoint, nil, &page); err != nil {
+		return err
+	}
+
+	var other string
+	for _, issue := range page.Issues {
+		if issue.Key != key {
+			other = issue.Key
+			break
+		}
+	}
+	if other == "" {
+		// The new issue is the only one on the board.
+		return nil
+	}
+
+	body := map[string]interface{}{"issues": []string{key}}
+	if rank == rankTop {
+		body["rankBeforeIssue"] = other
+	} else {
+		body["rankAfterIssue"] = other
+	}
+	_, err = doRequest(client, "PUT", "rest/agile/1.0/issue/rank", body, nil)
+	return err
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// recentProjectsMax is how many recently used projects are remembered.
+const recentProjectsMax = 5
+
+func recentProjectsPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lazyjira", "recent-projects.yaml"), nil
+}
+
+// recentProjects returns the keys of the projects issues were last created
+// in, most recent first. Any problem reading them just means there are none.
+func recentProjects() []string {
+	path, err := recentProjectsPath()
+	if err != nil {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var keys []string
+	if yaml.Unmarshal(b, &keys) != nil {
+		return nil
+	}
+	return keys
+}
+
+// rememberProject moves a project to the front of the recent projects.
+func rememberProject(key string) error {
+	keys := []string{key}
+	for _, k := range recentProjects() {
+		if !strings.EqualFold(k, key) && len(keys) < recentProjectsMax {
+			keys = append(keys, k)
+		}
+	}
+
+	path, err := recentProjectsPath()
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o600)
+}
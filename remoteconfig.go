@@ -0,0 +1,135 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// remoteConfigTTL is how long a fetched config is used before it is
+	// fetched again.
+	remoteConfigTTL     = 15 * time.Minute
+	remoteConfigTimeout = 10 * time.Second
+	// remoteConfigAuthEnv names the variable holding the Authorization
+	// header sent along when fetching a remote config.
+	remoteConfigAuthEnv = "LAZYJIRA_CONFIG_AUTH"
+	// apiKeyEnv names the variable that can hold the API token.
+	apiKeyEnv = "LAZYJIRA_API_KEY"
+)
+
+func isConfigURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// readConfig loads the config from a local path or a url. An empty location
+// means the default config file.
+func readConfig(location string) (Config, error) {
+	if location == "" {
+		path, err := defaultConfigPath()
+		if err != nil {
+			return Config{}, err
+		}
+		return loadConfig(path)
+	}
+	if !isConfigURL(location) {
+		return loadConfig(expandHome(location))
+	}
+	return loadRemoteConfig(location)
+}
+
+// loadRemoteConfig loads a config shared over HTTP. Credentials never come
+// from the remote file: the API token is taken from LAZYJIRA_API_KEY, then
+// username and token from the local config file when one exists.
+func loadRemoteConfig(url string) (Config, error) {
+	path, err := fetchRemoteConfig(url)
+	if err != nil {
+		return Config{}, err
+	}
+	c, err := loadConfig(path)
+	if err != nil {
+		return c, err
+	}
+
+	c.ApiKey = ""
+	if local, err := defaultConfigPath(); err == nil {
+		if _, err := os.Stat(local); err == nil {
+			lc, err := loadConfig(local)
+			if err != nil {
+				return c, err
+			}
+			if lc.Username != "" {
+				c.Username = lc.Username
+			}
+			c.ApiKey = lc.ApiKey
+		}
+	}
+	if key := os.Getenv(apiKeyEnv); key != "" {
+		var changed bool
+		if c.ApiKey, changed = cleanValue(key); changed {
+			debugf("%s: trimmed whitespace or quotes around the token", apiKeyEnv)
+		}
+	}
+	return c, nil
+}
+
+// fetchRemoteConfig downloads the config at url into the cache directory and
+// returns the path of the cached copy. A copy younger than remoteConfigTTL is
+// used as is, and a stale one when the download fails.
+func fetchRemoteConfig(url string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(url))
+	ext := ".yaml"
+	if u, _, _ := strings.Cut(url, "?"); isTOML(u) {
+		ext = ".toml"
+	}
+	path := filepath.Join(dir, "lazyjira", "config-"+hex.EncodeToString(sum[:8])+ext)
+
+	info, statErr := os.Stat(path)
+	if statErr == nil && time.Since(info.ModTime()) < remoteConfigTTL {
+		return path, nil
+	}
+
+	b, err := downloadConfig(url)
+	if err != nil {
+		if statErr == nil {
+			fmt.Fprintf(os.Stderr, "Warning: using cached config, %v\n", err)
+			return path, nil
+		}
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, b, 0o600)
+}
+
+func downloadConfig(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if auth := os.Getenv(remoteConfigAuthEnv); auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	client := http.Client{Timeout: remoteConfigTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch config: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("could not fetch config from %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// gitRemoteURL returns the url of the origin remote of the git repo in the
+// working directory, or an empty string outside of a repo.
+func gitRemoteURL() string {
+	out, err := exec.Command("git", "config", "--get", "remote.origin.url").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commitMessage returns the subject and the body of a commit of the git repo
+// in the working directory.
+func commitMessage(rev string) (subject, body string, err error) {
+	if err := exec.Command("git", "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return "", "", errors.New("-from-commit: not inside a git repository")
+	}
+	show := func(format string) (string, error) {
+		out, err := exec.Command("git", "show", "-s", "--format="+format, rev, "--").Output()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("-from-commit: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return strings.TrimSpace(string(out)), err
+	}
+	if subject, err = show("%s"); err != nil {
+		return "", "", err
+	}
+	if body, err = show("%b"); err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+// repoProject returns the project mapped to a remote url in repo_projects.
+// Patterns match the whole url and * stands for any run of characters. When
+// several patterns match, the longest one wins.
+func repoProject(mapping map[string]string, remote string) string {
+	if remote == "" {
+		return ""
+	}
+	patterns := make([]string, 0, len(mapping))
+	for p := range mapping {
+		patterns = append(patterns, p)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	for _, p := range patterns {
+		if matchURLPattern(p, remote) {
+			return mapping[p]
+		}
+	}
+	return ""
+}
+
+func matchURLPattern(pattern, s string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	ok, _ := regexp.MatchString(expr, s)
+	return ok
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// errUnknownReporter is a reporter that names no account, or more than one.
+// Rows with such a reporter are skipped, other lookup failures fail them.
+var errUnknownReporter = errors.New("no such reporter")
+
+// batchReporters looks up the reporters named in a batch file, each once.
+type batchReporters struct {
+	client *jira.Client
+	users  map[string]*jira.User
+	errs   map[string]error
+}
+
+func newBatchReporters(client *jira.Client) *batchReporters {
+	return &batchReporters{client: client, users: map[string]*jira.User{}, errs: map[string]error{}}
+}
+
+// find resolves a reporter given by email, account id, user name or display
+// name to the account, as the reporter field wants it.
+func (b *batchReporters) find(reporter string) (*jira.User, error) {
+	if u, ok := b.users[reporter]; ok {
+		return u, b.errs[reporter]
+	}
+	u, err := findReporter(b.client, reporter)
+	b.users[reporter], b.errs[reporter] = u, err
+	return u, err
+}
+
+func findReporter(client *jira.Client, reporter string) (*jira.User, error) {
+	var users []jira.User
+	// Cloud searches by query, Server and Data Center turn that down and
+	// want username.
+	resp, err := doRequest(client, "GET", "rest/api/2/user/search?query="+url.QueryEscape(reporter), nil, &users)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusBadRequest {
+		_, err = doRequest(client, "GET", "rest/api/2/user/search?username="+url.QueryEscape(reporter), nil, &users)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var match *jira.User
+	for i, u := range users {
+		if u.AccountID == reporter || strings.EqualFold(u.Name, reporter) || strings.EqualFold(u.EmailAddress, reporter) || strings.EqualFold(u.DisplayName, reporter) {
+			if match != nil {
+				return nil, fmt.Errorf("%w: %q matches more than one account", errUnknownReporter, reporter)
+			}
+			match = &users[i]
+		}
+	}
+	switch {
+	case match != nil:
+	case len(users) == 1:
+		match = &users[0]
+	case len(users) == 0:
+		return nil, fmt.Errorf("%w: no account found for %q", errUnknownReporter, reporter)
+	default:
+		return nil, fmt.Errorf("%w: %q matches %d accounts, give the email or account id", errUnknownReporter, reporter, len(users))
+	}
+	// Cloud refers to users by account id, Server and Data Center by name.
+	if match.AccountID != "" {
+		return &jira.User{AccountID: match.AccountID}, nil
+	}
+	return &jira.User{Name: match.Name}, nil
+}
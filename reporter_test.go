@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFindReporter(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		unknown bool
+	}{
+		{"by email", http.StatusOK, `[{"accountId":"a1","emailAddress":"ann@example.com"},{"accountId":"a2","emailAddress":"annie@example.com"}]`, "a1", false},
+		{"single result", http.StatusOK, `[{"accountId":"a1","displayName":"Ann"}]`, "a1", false},
+		{"no account", http.StatusOK, `[]`, "", true},
+		{"ambiguous", http.StatusOK, `[{"accountId":"a1"},{"accountId":"a2"}]`, "", true},
+		{"forbidden", http.StatusForbidden, `{"errorMessages":["no permission"]}`, "", false},
+		{"server error", http.StatusInternalServerError, `{}`, "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := jiraServer(t, tc.status, tc.body)
+			u, err := findReporter(client, "ann@example.com")
+			if tc.want != "" {
+				if err != nil || u.AccountID != tc.want {
+					t.Fatalf("findReporter() = %v, %v, want account %s", u, err, tc.want)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("want an error")
+			}
+			if errors.Is(err, errUnknownReporter) != tc.unknown {
+				t.Errorf("errors.Is(%v, errUnknownReporter) = %v, want %v", err, !tc.unknown, tc.unknown)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var errNoClipboardImage = errors.New("no image on the clipboard")
+
+// clipboardImageCommand is the command printing the clipboard image as PNG,
+// if there is a way to get at it on this system.
+func clipboardImageCommand() (*exec.Cmd, error) {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pngpaste", "-"}}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-paste", "--no-newline", "--type", "image/png"})
+		}
+		candidates = append(candidates, []string{"xclip", "-selection", "clipboard", "-target", "image/png", "-out"})
+	}
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c[0]); err == nil {
+			return exec.Command(c[0], c[1:]...), nil
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("clipboard images are not supported on %s", runtime.GOOS)
+	}
+	return nil, fmt.Errorf("reading clipboard images needs %s", candidates[len(candidates)-1][0])
+}
+
+// pngSignature starts every PNG file.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// readClipboardImage returns the image on the clipboard as PNG.
+func readClipboardImage() ([]byte, error) {
+	cmd, err := clipboardImageCommand()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.Output()
+	if err != nil || !bytes.HasPrefix(out, pngSignature) {
+		// The tools fail, or print whatever else is there, when the
+		// clipboard holds no image.
+		return nil, errNoClipboardImage
+	}
+	return out, nil
+}
+
+// screenshotMsg reports the clipboard image saved to path for attaching.
+type screenshotMsg struct {
+	path string
+	err  error
+}
+
+// grabScreenshot saves the clipboard image to a temporary PNG file.
+func grabScreenshot() tea.Cmd {
+	return func() tea.Msg {
+		image, err := readClipboardImage()
+		if err != nil {
+			return screenshotMsg{err: err}
+		}
+		f, err := os.CreateTemp("", "lazyjira-screenshot-*.png")
+		if err != nil {
+			return screenshotMsg{err: err}
+		}
+		defer f.Close()
+		if _, err := f.Write(image); err != nil {
+			os.Remove(f.Name())
+			return screenshotMsg{err: err}
+		}
+		return screenshotMsg{path: f.Name()}
+	}
+}
+
+// attachScreenshot uploads the saved clipboard image to the issue, then
+// removes the temporary file.
+func attachScreenshot(client *jira.Client, key, path string) tea.Cmd {
+	return func() tea.Msg {
+		defer os.Remove(path)
+		f, err := os.Open(path)
+		if err != nil {
+			return noticeMsg(fmt.Sprintf("Could not attach the screenshot: %v", err))
+		}
+		defer f.Close()
+		name := "screenshot-" + time.Now().Format("20060102-150405") + ".png"
+		if _, _, err := client.Issue.PostAttachment(key, f, name); err != nil {
+			return noticeMsg(fmt.Sprintf("Could not attach the screenshot: %v", err))
+		}
+		return noticeMsg("Attached " + name)
+	}
+}
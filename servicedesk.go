@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// serviceDesk is a JIRA Service Management desk, as returned by the
+// servicedeskapi.
+type serviceDesk struct {
+	ID         string `json:"id"`
+	ProjectKey string `json:"projectKey"`
+}
+
+type requestType struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	IssueTypeID string `json:"issueTypeId"`
+}
+
+// findServiceDesk returns the service desk behind a project, or nil when the
+// project is not a service desk or Service Management is not installed.
+func findServiceDesk(ctx context.Context, client *jira.Client, projectKey string) (*serviceDesk, error) {
+	for start := 0; ; {
+		var page struct {
+			Values     []serviceDesk `json:"values"`
+			Size       int           `json:"size"`
+			IsLastPage bool          `json:"isLastPage"`
+		}
+		resp, err := doRequestWithContext(ctx, client, "GET", fmt.Sprintf("rest/servicedeskapi/servicedesk?start=%d", start), nil, &page)
+		if err != nil {
+			if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, desk := range page.Values {
+			if strings.EqualFold(desk.ProjectKey, projectKey) {
+				return &desk, nil
+			}
+		}
+		if page.IsLastPage || page.Size == 0 {
+			return nil, nil
+		}
+		start += page.Size
+	}
+}
+
+// requestTypes lists the customer request types of a service desk.
+func requestTypes(client *jira.Client, desk *serviceDesk) ([]requestType, *jira.Response, error) {
+	var types []requestType
+	for start := 0; ; {
+		var page struct {
+			Values     []requestType `json:"values"`
+			Size       int           `json:"size"`
+			IsLastPage bool          `json:"isLastPage"`
+		}
+		endpoint := fmt.Sprintf("rest/servicedeskapi/servicedesk/%s/requesttype?start=%d", desk.ID, start)
+		resp, err := doRequest(client, "GET", endpoint, nil, &page)
+		if err != nil {
+			return nil, resp, err
+		}
+		types = append(types, page.Values...)
+		if page.IsLastPage || page.Size == 0 {
+			return types, resp, nil
+		}
+		start += page.Size
+	}
+}
+
+// requestTypeFor picks the configured request type, given by id or name, or
+// else the first request type backed by the given issue type. The response
+// is JIRA's answer when listing the request types.
+func requestTypeFor(client *jira.Client, desk *serviceDesk, issueTypeID, configured string) (*requestType, *jira.Response, error) {
+	types, resp, err := requestTypes(client, desk)
+	if err != nil {
+		return nil, resp, err
+	}
+	if configured != "" {
+		rt, err := findRequestType(types, desk, configured)
+		return rt, resp, err
+	}
+	for _, t := range types {
+		if t.IssueTypeID == issueTypeID {
+			return &t, resp, nil
+		}
+	}
+	return nil, resp, fmt.Errorf("no request type in service desk %s uses this issue type", desk.ProjectKey)
+}
+
+// findRequestType returns the request type with the given id or name.
+func findRequestType(types []requestType, desk *serviceDesk, idOrName string) (*requestType, error) {
+	names := make([]string, len(types))
+	for i, t := range types {
+		if t.ID == idOrName || strings.EqualFold(t.Name, idOrName) {
+			return &types[i], nil
+		}
+		names[i] = t.Name
+	}
+	return nil, fmt.Errorf("service desk %s has no request type %q, pick one of: %s", desk.ProjectKey, idOrName, strings.Join(names, ", "))
+}
+
+// checkRequestType makes sure create_issue.default_request_type is one of
+// the request types of the project's service desk, which is returned.
+func checkRequestType(client *jira.Client, c CreateIssueConfig) (*serviceDesk, error) {
+	desk, err := findServiceDesk(context.Background(), client, c.Project)
+	if err != nil {
+		return nil, err
+	}
+	if desk == nil {
+		return nil, fmt.Errorf("create_issue.default_request_type is set but %s is not a service desk", c.Project)
+	}
+	if _, _, err := requestTypeFor(client, desk, "", c.DefaultRequestType); err != nil {
+		return nil, err
+	}
+	return desk, nil
+}
+
+func validateEmail(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != strings.TrimSpace(s) {
+		return errors.New("not a valid email address")
+	}
+	return nil
+}
+
+// createRequest files the issue as a customer request in the background,
+// see sendRequest.
+func createRequest(client *jira.Client, desk *serviceDesk, issueTypeID, requestType string, issue *jira.Issue, onBehalfOf string) tea.Cmd {
+	return func() tea.Msg {
+		created, resp, err := sendRequest(client, desk, issueTypeID, requestType, issue, onBehalfOf)
+		if err != nil {
+			return failedMsg(resp, err)
+		}
+		return issueCreatedMsg{issue: created}
+	}
+}
+
+// sendRequest files the issue as a customer request of the configured
+// request type, or else the one behind the issue type, raised on behalf of
+// the given email address unless that is empty. Only the fields set on the
+// issue are sent along.
+func sendRequest(client *jira.Client, desk *serviceDesk, issueTypeID, requestType string, issue *jira.Issue, onBehalfOf string) (*jira.Issue, *jira.Response, error) {
+	rt, resp, err := requestTypeFor(client, desk, issueTypeID, requestType)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	values := map[string]interface{}{}
+	for k, v := range issue.Fields.Unknowns {
+		values[k] = v
+	}
+	values["summary"] = issue.Fields.Summary
+	if issue.Fields.Description != "" {
+		values["description"] = issue.Fields.Description
+	}
+
+	body := map[string]interface{}{
+		"serviceDeskId":      desk.ID,
+		"requestTypeId":      rt.ID,
+		"requestFieldValues": values,
+	}
+	if onBehalfOf = strings.TrimSpace(onBehalfOf); onBehalfOf != "" {
+		body["raiseOnBehalfOf"] = onBehalfOf
+	}
+	var created struct {
+		IssueID  string `json:"issueId"`
+		IssueKey string `json:"issueKey"`
+		Links    struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}
+	resp, err = doRequest(client, "POST", "rest/servicedeskapi/request", body, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &jira.Issue{ID: created.IssueID, Key: created.IssueKey, Self: created.Links.Self}, resp, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// sprintFieldType is the schema of JIRA Software's sprint field.
+const sprintFieldType = "com.pyxis.greenhopper.jira:gh-sprint"
+
+// findSprint looks up an active or future sprint by name on the scrum boards
+// of a project. Several boards often share a sprint, so matches are told
+// apart by sprint id.
+func findSprint(client *jira.Client, project, name string) (*jira.Sprint, error) {
+	boards, _, err := client.Board.GetAllBoards(&jira.BoardListOptions{ProjectKeyOrID: project, BoardType: "scrum"})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []jira.Sprint
+	seen := map[int]bool{}
+	for _, board := range boards.Values {
+		for start := 0; ; {
+			sprints, _, err := client.Board.GetAllSprintsWithOptions(board.ID, &jira.GetAllSprintsOptions{
+				State:         "active,future",
+				SearchOptions: jira.SearchOptions{StartAt: start},
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, s := range sprints.Values {
+				if strings.EqualFold(s.Name, name) && !seen[s.ID] {
+					seen[s.ID] = true
+					matches = append(matches, s)
+				}
+			}
+			if sprints.IsLast || len(sprints.Values) == 0 {
+				break
+			}
+			start += len(sprints.Values)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no active or future sprint named %q in project %s", name, project)
+	case 1:
+		return &matches[0], nil
+	}
+	candidates := make([]string, len(matches))
+	for i, s := range matches {
+		candidates[i] = fmt.Sprintf("%s (id %d, %s)", s.Name, s.ID, s.State)
+	}
+	return nil, fmt.Errorf("sprint name %q is ambiguous: %s", name, strings.Join(candidates, ", "))
+}
+
+// sprintField returns the sprint field on the create screen of an issue
+// type.
+func sprintField(fields []*formField) (*formField, error) {
+	for _, f := range fields {
+		if f.schema.Custom == sprintFieldType {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("the sprint field is not on the create screen")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type selfLoadedMsg struct {
+	user *jira.User
+}
+
+// loadSelf looks up the logged in user for the status bar. Failing only
+// leaves the user out of it.
+func loadSelf(ctx context.Context, client *jira.Client) tea.Cmd {
+	return func() tea.Msg {
+		user, _, err := client.User.GetSelfWithContext(ctx)
+		if err != nil {
+			return nil
+		}
+		return selfLoadedMsg{user}
+	}
+}
+
+// statusBarView shows which site, user and project the session works with.
+func (m Model) statusBarView() string {
+	s := m.styles
+
+	site := m.config.JiraUrl
+	if u, err := url.Parse(site); err == nil && u.Host != "" {
+		site = u.Host
+	}
+	parts := []string{site}
+	switch {
+	case m.self != nil && m.self.DisplayName != "":
+		parts = append(parts, m.self.DisplayName)
+	case m.config.Username != "":
+		parts = append(parts, m.config.Username)
+	}
+	if key := m.issue.Fields.Project.Key; key != "" {
+		parts = append(parts, s.StatusHeader.Render(key))
+	}
+	if notice := m.config.tokenExpiryNotice(time.Now()); notice != "" {
+		parts = append(parts, s.Warning.Render(notice))
+	}
+
+	return s.Status.
+		Width(m.width - s.Status.GetHorizontalBorderSize()).
+		Render(strings.Join(parts, s.Help.Render(" · ")))
+}
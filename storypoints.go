@@ -0,0 +1,37 @@
+package main
+
+import "strings"
+
+// storyPointsType is the schema type of the "Story point estimate" field
+// of team-managed projects. Company-managed projects use a plain number
+// field called "Story Points" instead, under a different id.
+const storyPointsType = "com.pyxis.greenhopper.jira:jsw-story-points"
+
+// storyPointsNames are the names the story points field goes by.
+var storyPointsNames = []string{"Story Points", "Story point estimate"}
+
+func isStoryPointsName(name string) bool {
+	for _, n := range storyPointsNames {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// storyPointsField returns whichever story points field the create screen
+// has, so that either name sets it in team-managed and company-managed
+// projects alike.
+func storyPointsField(fields []*formField) *formField {
+	for _, f := range fields {
+		if f.schema.Custom == storyPointsType {
+			return f
+		}
+	}
+	for _, f := range fields {
+		if f.schema.Type == "number" && isStoryPointsName(f.name) {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// writeSessionSummary lists the issues created in one go, from the TUI or a
+// batch file, with their summaries and browse urls, ready to paste into a
+// standup note or a PR description.
+func writeSessionSummary(w io.Writer, c Config, issues []*jira.Issue) error {
+	fmt.Fprintf(w, "\nCreated %d issues:\n", len(issues))
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tSUMMARY\tURL\t")
+	for _, issue := range issues {
+		summary := ""
+		if issue.Fields != nil {
+			summary = issue.Fields.Summary
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\n", issue.Key, summary, browseURL(c, issue.Key))
+	}
+	return tw.Flush()
+}
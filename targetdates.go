@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The target start and end date fields of Advanced Roadmaps.
+const (
+	targetStartType = "com.atlassian.jpo:jpo-custom-field-baseline-start"
+	targetEndType   = "com.atlassian.jpo:jpo-custom-field-baseline-end"
+)
+
+// pairTargetDates ties the target start and end fields to each other, so
+// that an end before the start is caught whichever is entered last.
+func pairTargetDates(fields []*formField) {
+	var start, end *formField
+	for _, f := range fields {
+		switch f.schema.Custom {
+		case targetStartType:
+			start = f
+		case targetEndType:
+			end = f
+		}
+	}
+	if start == nil || end == nil {
+		return
+	}
+	start.end, end.start = end, start
+}
+
+// validateRange checks a date against the other end of its range, if f is
+// one end of one. Dates that do not parse are left to validateDate.
+func (f *formField) validateRange(s string) error {
+	date, err := time.Parse(dateLayout, strings.TrimSpace(s))
+	if err != nil {
+		return nil
+	}
+	if f.start != nil {
+		if start, err := time.Parse(dateLayout, strings.TrimSpace(f.start.value)); err == nil && date.Before(start) {
+			return fmt.Errorf("%s must not be before %s", f.name, f.start.name)
+		}
+	}
+	if f.end != nil {
+		if end, err := time.Parse(dateLayout, strings.TrimSpace(f.end.value)); err == nil && end.Before(date) {
+			return fmt.Errorf("%s must not be after %s", f.name, f.end.name)
+		}
+	}
+	return nil
+}
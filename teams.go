@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// Schema types of the team fields: the Cloud one, and the one from Advanced
+// Roadmaps used by company-managed and Data Center projects.
+const (
+	teamFieldCloud     = "com.atlassian.jira.plugin.system.customfieldtypes:atlassian-team"
+	teamFieldPortfolio = "com.atlassian.teams:rm-teams-custom-field-team"
+)
+
+func isTeamField(f *formField) bool {
+	return f.schema.Custom == teamFieldCloud || f.schema.Custom == teamFieldPortfolio
+}
+
+// teamsToLoad reports which team fields of the project create metadata does
+// not list the teams for: whether there is an Advanced Roadmaps one, and the
+// id of a Cloud one, if any.
+func teamsToLoad(project *jira.MetaProject) (portfolio bool, cloud string) {
+	for _, t := range project.IssueTypes {
+		if t == nil {
+			continue
+		}
+		for id := range t.Fields {
+			meta, err := t.Fields.MarshalMap(id)
+			if err != nil || meta == nil {
+				continue
+			}
+			f := newFormField(id, meta)
+			if len(f.allowed) > 0 {
+				continue
+			}
+			switch f.schema.Custom {
+			case teamFieldPortfolio:
+				portfolio = true
+			case teamFieldCloud:
+				cloud = id
+			}
+		}
+	}
+	return portfolio, cloud
+}
+
+// loadTeams lists the Advanced Roadmaps teams.
+func loadTeams(ctx context.Context, client *jira.Client) ([]allowedValue, error) {
+	var teams []struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	body := map[string]interface{}{"maxResults": 200}
+	if _, err := doRequestWithContext(ctx, client, "POST", "rest/teams/1.0/teams/find", body, &teams); err != nil {
+		return nil, err
+	}
+	values := make([]allowedValue, len(teams))
+	for i, t := range teams {
+		values[i] = allowedValue{id: strconv.Itoa(t.ID), label: t.Title}
+	}
+	return values, nil
+}
+
+// loadCloudTeams lists the teams of a Cloud site, as JQL suggests them for
+// the team field with the given id. The suggested values are the team ids
+// the field takes.
+func loadCloudTeams(ctx context.Context, client *jira.Client, fieldID string) ([]allowedValue, error) {
+	clause := "cf[" + strings.TrimPrefix(fieldID, "customfield_") + "]"
+	var suggestions struct {
+		Results []struct {
+			Value       string `json:"value"`
+			DisplayName string `json:"displayName"`
+		} `json:"results"`
+	}
+	endpoint := "rest/api/2/jql/autocompletedata/suggestions?fieldValue=&fieldName=" + url.QueryEscape(clause)
+	if _, err := doRequestWithContext(ctx, client, "GET", endpoint, nil, &suggestions); err != nil {
+		return nil, err
+	}
+	// Suggestions highlight the part matching what was typed.
+	unbold := strings.NewReplacer("<b>", "", "</b>", "")
+	var values []allowedValue
+	for _, r := range suggestions.Results {
+		if r.Value != "" {
+			values = append(values, allowedValue{id: r.Value, label: unbold.Replace(r.DisplayName)})
+		}
+	}
+	return values, nil
+}
+
+// useTeams turns team fields into a select of teams, those of Advanced
+// Roadmaps or of the Cloud site depending on the field type, and makes them
+// send the bare team id, which is what both team field types expect.
+func useTeams(fields []*formField, teams, cloudTeams []allowedValue) {
+	for _, f := range fields {
+		if !isTeamField(f) {
+			continue
+		}
+		portfolio := f.schema.Custom == teamFieldPortfolio
+		if len(f.allowed) == 0 {
+			if portfolio {
+				f.allowed = teams
+			} else {
+				f.allowed = cloudTeams
+			}
+		}
+		f.encode = func(v string) interface{} {
+			if n, err := strconv.Atoi(v); err == nil && portfolio {
+				return n
+			}
+			return v
+		}
+	}
+}
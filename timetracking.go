@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+const (
+	// timeTrackingField is only in the create metadata when time tracking is
+	// enabled on the project.
+	timeTrackingField = "timetracking"
+	// timeSpentField holds the work to log once the issue exists. It is not
+	// part of the create request.
+	timeSpentField = "timespent"
+)
+
+// durationPattern matches JIRA's time syntax, like 3h 30m or 1w 2d, and
+// plain numbers, which JIRA reads in its default unit.
+var durationPattern = regexp.MustCompile(`^(\d+(\.\d+)?|(\d+(\.\d+)?[wdhm]\s*)+)$`)
+
+// timeTrackingFields turns the time tracking field into an original estimate
+// input and a time spent input.
+func timeTrackingFields(meta tcontainer.MarshalMap) []*formField {
+	estimate := newFormField(timeTrackingField, meta)
+	estimate.name = "Original estimate"
+	estimate.encode = func(s string) interface{} {
+		return map[string]string{"originalEstimate": s}
+	}
+	spent := &formField{id: timeSpentField, name: "Time spent", schema: estimate.schema}
+	return []*formField{estimate, spent}
+}
+
+func (f *formField) validateDuration(s string) error {
+	if err := f.validate(s); err != nil {
+		return err
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if !durationPattern.MatchString(s) {
+		return fmt.Errorf("%s must be a time like 2h 30m or 1d", f.name)
+	}
+	return nil
+}
+
+// timeSpent is the time spent entered on the form, if the project tracks
+// time at all.
+func timeSpent(fields []*formField) string {
+	if f := fieldByID(fields, timeSpentField); f != nil {
+		return strings.TrimSpace(f.value)
+	}
+	return ""
+}
+
+// logWork adds a worklog for the time spent entered on the form, reporting
+// back on the success screen.
+func logWork(client *jira.Client, key, spent string) tea.Cmd {
+	return func() tea.Msg {
+		record := &jira.WorklogRecord{TimeSpent: spent}
+		if _, _, err := client.Issue.AddWorklogRecord(key, record); err != nil {
+			return noticeMsg(fmt.Sprintf("Could not log %s: %v", spent, err))
+		}
+		return noticeMsg("Logged " + spent)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultTokenWarnDays is how long before token_expires the TUI starts
+// warning, unless token_warn_days says otherwise.
+const defaultTokenWarnDays = 14
+
+func validateTokenExpires(s string) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := time.Parse(dateLayout, s); err != nil {
+		return fmt.Errorf("token_expires %q is not a date like 2024-01-31", s)
+	}
+	return nil
+}
+
+// tokenExpiryNotice warns that the API token expires soon, or has expired,
+// going by token_expires. It is empty while the expiry is further off than
+// the warning window, or not known.
+func (c Config) tokenExpiryNotice(now time.Time) string {
+	expires, err := time.Parse(dateLayout, c.TokenExpires)
+	if err != nil {
+		return ""
+	}
+	window := c.TokenWarnDays
+	if window <= 0 {
+		window = defaultTokenWarnDays
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(expires.Sub(today).Hours() / 24)
+	switch {
+	case days > window:
+		return ""
+	case days < 0:
+		return "API token expired on " + c.TokenExpires + ", run lazyjira login"
+	case days == 0:
+		return "API token expires today, run lazyjira login"
+	case days == 1:
+		return "API token expires tomorrow"
+	default:
+		return fmt.Sprintf("API token expires in %d days", days)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/charmbracelet/huh"
+)
+
+// findTransition returns the transition of an issue with the given name,
+// matching either the transition or the status it leads to.
+func findTransition(client *jira.Client, key, name string) (*jira.Transition, error) {
+	transitions, _, err := client.Issue.GetTransitions(key)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, name) || strings.EqualFold(t.To.Name, name) {
+			return &t, nil
+		}
+	}
+	names := make([]string, len(transitions))
+	for i, t := range transitions {
+		names[i] = t.Name
+	}
+	return nil, fmt.Errorf("no transition %q from the current status, pick one of: %s", name, strings.Join(names, ", "))
+}
+
+// pickResolution asks for a resolution out of the ones configured in JIRA.
+func pickResolution(client *jira.Client, transition string) (string, error) {
+	resolutions, _, err := client.Resolution.GetList()
+	if err != nil {
+		return "", err
+	}
+	options := make([]huh.Option[string], len(resolutions))
+	for i, r := range resolutions {
+		options[i] = huh.NewOption(r.Name, r.Name)
+	}
+	var resolution string
+	err = huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title(fmt.Sprintf("Resolution for %s:", transition)).
+			Options(options...).
+			Value(&resolution),
+	)).Run()
+	return resolution, err
+}
+
+// transitionIssue moves a created issue through the named transition. A
+// resolution is only sent when the transition screen has the field, and is
+// asked for when the transition requires one and ask is set. The resolution
+// used is returned so later issues can reuse it.
+func transitionIssue(client *jira.Client, key, name, resolution string, ask bool) (string, error) {
+	t, err := findTransition(client, key, name)
+	if err != nil {
+		return resolution, err
+	}
+
+	field, onScreen := t.Fields["resolution"]
+	switch {
+	case !onScreen && resolution != "":
+		fmt.Fprintf(os.Stderr, "Warning: the %s transition does not take a resolution, %s is left unresolved\n", t.Name, key)
+	case onScreen && field.Required && resolution == "" && ask:
+		picked, err := pickResolution(client, t.Name)
+		if err != nil {
+			return resolution, err
+		}
+		resolution = picked
+	case onScreen && field.Required && resolution == "":
+		return resolution, fmt.Errorf("the %s transition requires a resolution, use -resolution", t.Name)
+	}
+
+	payload := jira.CreateTransitionPayload{Transition: jira.TransitionPayload{ID: t.ID}}
+	if onScreen && resolution != "" {
+		payload.Fields.Resolution = &jira.Resolution{Name: resolution}
+	}
+	_, err = doRequest(client, "POST", fmt.Sprintf("rest/api/2/issue/%s/transitions", key), payload, nil)
+	return resolution, err
+}
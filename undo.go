@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// undoWindow is how many seconds a created issue can still be deleted from
+// the success screen.
+const undoWindow = 10
+
+// undoTickMsg counts down the undo window of the issue with the given key.
+type undoTickMsg string
+
+type issueDeletedMsg struct {
+	key string
+}
+
+type deleteFailedMsg struct {
+	key string
+	err error
+	// forbidden is set when the user lacks the delete permission.
+	forbidden bool
+}
+
+func undoTick(key string) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return undoTickMsg(key)
+	})
+}
+
+func deleteIssue(client *jira.Client, key string) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := client.Issue.Delete(key)
+		if err != nil {
+			return deleteFailedMsg{
+				key:       key,
+				err:       jira.NewJiraError(resp, err),
+				forbidden: resp != nil && resp.StatusCode == http.StatusForbidden,
+			}
+		}
+		return issueDeletedMsg{key}
+	}
+}
+
+func (msg deleteFailedMsg) notice() string {
+	if msg.forbidden {
+		return fmt.Sprintf("Not allowed to delete %s", msg.key)
+	}
+	return fmt.Sprintf("Could not delete %s: %v", msg.key, msg.err)
+}
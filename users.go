@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// isUserField reports whether f takes a user, like the assignee, or several,
+// like an approvers field.
+func isUserField(f *formField) bool {
+	return f.schema.Type == "user" || (f.schema.Type == "array" && f.schema.Items == "user")
+}
+
+// needsUsers reports whether any issue type of the project has a user field
+// that create metadata lists no users for.
+func needsUsers(project *jira.MetaProject) bool {
+	for _, t := range project.IssueTypes {
+		if t == nil {
+			continue
+		}
+		for id := range t.Fields {
+			meta, err := t.Fields.MarshalMap(id)
+			if err != nil || meta == nil {
+				continue
+			}
+			if f := newFormField(id, meta); isUserField(f) && len(f.allowed) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadUsers lists the users issues of the project can be assigned to.
+func loadUsers(ctx context.Context, client *jira.Client, project string) ([]jira.User, error) {
+	var users []jira.User
+	endpoint := fmt.Sprintf("rest/api/2/user/assignable/search?project=%s&maxResults=1000", url.QueryEscape(project))
+	if _, err := doRequestWithContext(ctx, client, "GET", endpoint, nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// userValues turns users into select options. People sharing a display name
+// get something added so they can be told apart, see userHint.
+func userValues(users []jira.User) []allowedValue {
+	named := map[string][]jira.User{}
+	for _, u := range users {
+		named[u.DisplayName] = append(named[u.DisplayName], u)
+	}
+
+	values := make([]allowedValue, 0, len(users))
+	for _, u := range users {
+		v := allowedValue{id: u.AccountID, label: u.DisplayName, ref: "accountId"}
+		if v.id == "" {
+			// Server and Data Center refer to users by name.
+			v.id, v.ref = u.Name, "name"
+		}
+		if v.id == "" {
+			continue
+		}
+		if same := named[u.DisplayName]; len(same) > 1 {
+			v.label += " (" + userHint(u, same) + ")"
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// userHint tells u apart from the others sharing its display name: its
+// email domain when no one else there has it, or else its full email, the
+// end of its account id or its username.
+func userHint(u jira.User, same []jira.User) string {
+	if domain := emailDomain(u.EmailAddress); domain != "" {
+		shared := 0
+		for _, o := range same {
+			if emailDomain(o.EmailAddress) == domain {
+				shared++
+			}
+		}
+		if shared == 1 {
+			return domain
+		}
+	}
+	if u.EmailAddress != "" {
+		return u.EmailAddress
+	}
+	if u.AccountID != "" {
+		return "…" + u.AccountID[max(0, len(u.AccountID)-6):]
+	}
+	return u.Name
+}
+
+func emailDomain(email string) string {
+	_, domain, _ := strings.Cut(email, "@")
+	return strings.ToLower(domain)
+}
+
+// useUsers offers the project's assignable users on user fields. Fields
+// taking several users become a multi-select sending the picked accounts.
+func useUsers(fields []*formField, users []allowedValue) {
+	for _, f := range fields {
+		if isUserField(f) && len(f.allowed) == 0 {
+			f.allowed = users
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// autoWatch reports whether created issues are watched explicitly. JIRA
+// usually has creators watch their issues already, but that can be turned
+// off.
+func (c CreateIssueConfig) autoWatch() bool {
+	return c.AutoWatch == nil || *c.AutoWatch
+}
+
+// userRef is how the watchers API refers to a user: the account id on
+// Cloud, the user name on Server and Data Center.
+func userRef(u *jira.User) string {
+	if u.AccountID != "" {
+		return u.AccountID
+	}
+	return u.Name
+}
+
+func watchIssue(client *jira.Client, key string, self *jira.User) error {
+	_, err := doRequest(client, "POST", fmt.Sprintf("rest/api/2/issue/%s/watchers", key), userRef(self), nil)
+	return err
+}
+
+func voteIssue(client *jira.Client, key string) error {
+	_, err := doRequest(client, "POST", fmt.Sprintf("rest/api/2/issue/%s/votes", key), nil, nil)
+	return err
+}